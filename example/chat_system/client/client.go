@@ -26,7 +26,7 @@ func main() {
 		log.Fatalf("Client could not connect to server on 9003. [%s]", err)
 	}
 
-	defer conn.Close()
+	defer conn.Close(context.Background())
 
 	c := protos.NewChatServiceClient(conn)
 	msg := &protos.Message{
@@ -0,0 +1,116 @@
+package grpc
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	defaultAutoscaleInterval    = 5 * time.Second
+	defaultScaleDownCooldown    = time.Minute
+	defaultMaxConcurrentStreams = 100
+	defaultTargetStreamsRatio   = 0.75
+)
+
+const (
+	ScaleUpReason   = "in-flight RPCs per connection above target"
+	ScaleDownReason = "in-flight RPCs per connection below target"
+	ScaleMinReason  = "pool size below min size"
+	ScaleMaxReason  = "pool size above max size"
+)
+
+// AutoscaleConfig configures the pool to grow and shrink between MinSize and MaxSize connections,
+// based upon the average no of RPCs in-flight per connection
+type AutoscaleConfig struct {
+	MinSize int
+	MaxSize int
+	// TargetInFlight is the average no of RPCs in-flight per connection above which the pool grows.
+	// Defaults to 75% of MaxConcurrentStreams
+	TargetInFlight float64
+	// MaxConcurrentStreams is the limit of concurrent streams the server imposes on each connection
+	MaxConcurrentStreams uint32
+	// Interval at which the load on the pool is evaluated
+	Interval time.Duration
+	// ScaleDownCooldown is the min time since the last scaling before the pool shrinks
+	ScaleDownCooldown time.Duration
+	// OnEvent gets called with every scaling decision
+	OnEvent func(ScaleEvent)
+}
+
+// ScaleEvent reports a scaling decision taken by the autoscaler
+type ScaleEvent struct {
+	From        int
+	To          int
+	AvgInFlight float64
+	Reason      string
+	Err         error
+	OccurredAt  time.Time
+}
+
+// WithAutoscale turns on the autoscaling of the pool
+func WithAutoscale(cfg AutoscaleConfig) Option {
+	return optionFunc(func(o *options) {
+		o.autoscale = &cfg
+	})
+}
+
+func (cfg AutoscaleConfig) withDefaults(poolSize int) AutoscaleConfig {
+	cfg.MinSize = GetOrDefault[int](cfg.MinSize, 1)
+	cfg.MaxSize = GetOrDefault[int](cfg.MaxSize, poolSize)
+	if cfg.MaxSize < cfg.MinSize {
+		cfg.MaxSize = cfg.MinSize
+	}
+	if cfg.MaxConcurrentStreams == 0 {
+		cfg.MaxConcurrentStreams = defaultMaxConcurrentStreams
+	}
+	if cfg.TargetInFlight <= 0 {
+		cfg.TargetInFlight = defaultTargetStreamsRatio * float64(cfg.MaxConcurrentStreams)
+	}
+	cfg.Interval = GetOrDefault[time.Duration](cfg.Interval, defaultAutoscaleInterval)
+	cfg.ScaleDownCooldown = GetOrDefault[time.Duration](cfg.ScaleDownCooldown, defaultScaleDownCooldown)
+	return cfg
+}
+
+// autoscale evaluates the load on the pool every interval and resizes it, till the pool is closed
+func (pool *clientConnPool) autoscale(cfg AutoscaleConfig) {
+	lastScaledAt := pool.opts.clock.Now()
+	for pool.wait(cfg.Interval) {
+		conns := pool.snapshot()
+		n := len(conns)
+		if n == 0 {
+			continue
+		}
+		total := int64(0)
+		for _, c := range conns {
+			total += c.info().InFlight
+		}
+		avg := float64(total) / float64(n)
+		// no of connections needed to keep the average under target
+		needed := int(math.Ceil(float64(total) / cfg.TargetInFlight))
+
+		to, reason := n, ""
+		switch {
+		case n < cfg.MinSize:
+			to, reason = cfg.MinSize, ScaleMinReason
+		case n > cfg.MaxSize:
+			to, reason = cfg.MaxSize, ScaleMaxReason
+		case avg > cfg.TargetInFlight && n < cfg.MaxSize:
+			to, reason = needed, ScaleUpReason
+			if to > cfg.MaxSize {
+				to = cfg.MaxSize
+			}
+		case needed < n && n > cfg.MinSize && pool.opts.clock.Now().Sub(lastScaledAt) >= cfg.ScaleDownCooldown:
+			// shrink one connection at a time, to not cause a burst of refreshes
+			to, reason = n-1, ScaleDownReason
+		}
+		if to == n {
+			continue
+		}
+
+		err := pool.Resize(to)
+		lastScaledAt = pool.opts.clock.Now()
+		if cfg.OnEvent != nil {
+			cfg.OnEvent(ScaleEvent{From: n, To: to, AvgInFlight: avg, Reason: reason, Err: err, OccurredAt: lastScaledAt})
+		}
+	}
+}
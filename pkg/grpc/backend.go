@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+)
+
+// PeerDistribution returns the no of connections of the pool landed on every backend, keyed on the remote address.
+// Connections which have not connected to a backend yet are left out
+func (pool *clientConnPool) PeerDistribution() map[string]int {
+	dist := make(map[string]int)
+	for _, c := range pool.snapshot() {
+		conn, ok := c.current()
+		if !ok {
+			continue
+		}
+		if peer := conn.stats.Peer(); peer != "" {
+			dist[peer]++
+		}
+	}
+	return dist
+}
+
+// dialDistinct dials a new connection, re-dialling it till it lands on a backend which no other connection
+// of the pool is on. It gives up after the configured no of attempts and keeps the last connection dialled.
+// replaced is the connection the new one is going to replace, it is not counted as using its backend
+func (pool *clientConnPool) dialDistinct(target string, replaced *sharedConn) (*sharedConn, error) {
+	attempts := pool.opts.distinctBackendAttempts
+	for i := 1; ; i++ {
+		conn, err := pool.dial(target)
+		if err != nil || attempts <= 1 {
+			return conn, err
+		}
+
+		// the backend of a connection is only known once it has connected
+		ctx, cancel := context.WithTimeout(pool.ctx, pool.opts.warmupTimeout)
+		err = waitForReady(ctx, conn.ClientConn)
+		cancel()
+
+		if pool.claimBackend(conn, replaced, err != nil || i >= attempts) {
+			return conn, nil
+		}
+		_ = conn.Close()
+	}
+}
+
+// claimBackend records conn as using its backend, if no other live connection of the pool is on the same backend.
+// With force, conn is recorded irrespective of the other connections
+func (pool *clientConnPool) claimBackend(conn, replaced *sharedConn, force bool) bool {
+	pool.backendsMu.Lock()
+	defer pool.backendsMu.Unlock()
+
+	peer := conn.stats.Peer()
+	for other := range pool.backends {
+		// connection got closed or retired, it no longer holds its backend
+		if other.ctx.Err() != nil {
+			delete(pool.backends, other)
+			continue
+		}
+		if !force && other != replaced && peer != "" && other.stats.Peer() == peer {
+			return false
+		}
+	}
+	pool.backends[conn] = struct{}{}
+	return true
+}
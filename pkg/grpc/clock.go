@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"time"
+)
+
+// Clock is the source of time for the pool. It is used for the lifetime of the connections
+// and for scheduling all the background jobs, and can be overridden to control time in tests
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a single event timer created by a Clock, which sends the current time on C() once it fires
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+}
+
+// WithClock overrides the Clock of the pool
+func WithClock(c Clock) Option {
+	return optionFunc(func(o *options) {
+		o.clock = c
+	})
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{t: time.NewTimer(d)} }
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (r realTimer) C() <-chan time.Time { return r.t.C }
+
+func (r realTimer) Stop() bool { return r.t.Stop() }
@@ -0,0 +1,201 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/arpit006/go-grpc-conn-pool/pkg/grpc"
+	"github.com/arpit006/go-grpc-conn-pool/pkg/grpc/grpctest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startServer starts a grpc server serving the health service, which is stopped once the test is over
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	hpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+func TestLifetimeExpiresOnClock(t *testing.T) {
+	clk := grpctest.NewFakeClock(time.Unix(1000, 0))
+	// the background refresh never fires, so that the expired connections are not replaced
+	cfg := v2.ClientConfigBuilder().
+		WithTarget(startServer(t)).
+		WithPoolSize(2).
+		WithMinReadyConns(2).
+		WithClock(clk).
+		WithLifetimePolicy(v2.FixedLifetime(time.Minute)).
+		WithRefreshInterval(time.Hour).
+		Build()
+	c, err := v2.NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+
+	clk.Advance(30 * time.Second)
+	healthy, refresh := v2.ConnHealth(c)
+	for i := range healthy {
+		if !healthy[i] || refresh[i] {
+			t.Fatalf("connection [%d] before its lifetime: healthy [%v], refresh [%v]", i, healthy[i], refresh[i])
+		}
+	}
+
+	clk.Advance(30 * time.Second)
+	healthy, refresh = v2.ConnHealth(c)
+	for i := range healthy {
+		if healthy[i] || !refresh[i] {
+			t.Fatalf("connection [%d] past its lifetime: healthy [%v], refresh [%v]", i, healthy[i], refresh[i])
+		}
+	}
+}
+
+func TestSchedulerTicksOnClock(t *testing.T) {
+	clk := grpctest.NewFakeClock(time.Unix(1000, 0))
+	ticks := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		v2.RunScheduler(ctx, 10*time.Second, 0.2, clk, func() { ticks <- struct{}{} })
+	}()
+
+	for i := 0; i < 3; i++ {
+		clk.BlockUntil(1)
+		// the jittered interval is between 8s and 12s
+		clk.Advance(7900 * time.Millisecond)
+		select {
+		case <-ticks:
+			t.Fatalf("tick [%d] before the min interval", i)
+		default:
+		}
+		clk.Advance(4200 * time.Millisecond)
+		select {
+		case <-ticks:
+		case <-time.After(time.Second):
+			t.Fatalf("no tick [%d] after the max interval", i)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler still running after its context is done")
+	}
+}
+
+func TestReplacedConnDrainsOnClock(t *testing.T) {
+	clk := grpctest.NewFakeClock(time.Unix(1000, 0))
+	cfg := v2.ClientConfigBuilder().
+		WithTarget(startServer(t)).
+		WithMinReadyConns(1).
+		WithClock(clk).
+		WithLifetimePolicy(v2.FixedLifetime(time.Minute)).
+		WithRefreshInterval(10 * time.Second).
+		Build()
+	c, err := v2.NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+
+	stream, err := hpb.NewHealthClient(c).Watch(context.Background(), &hpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	closed := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		closed <- err
+	}()
+
+	// the connection expires and gets replaced, while the stream keeps the old one open
+	clk.BlockUntil(1)
+	clk.Advance(time.Minute)
+	clk.BlockUntil(2)
+	select {
+	case err := <-closed:
+		t.Fatalf("stream finished before the drain timeout, error is: [%v]", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	clk.Advance(30 * time.Second)
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("replaced connection not closed once the drain timeout is reached")
+	}
+}
+
+func TestSchedulerClampsJitter(t *testing.T) {
+	clk := grpctest.NewFakeClock(time.Unix(1000, 0))
+	ticks := make(chan struct{}, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go v2.RunScheduler(ctx, 10*time.Second, 5, clk, func() { ticks <- struct{}{} })
+
+	for i := 0; i < 20; i++ {
+		clk.BlockUntil(1)
+		// the jitter is capped at 0.9, so the interval is at least 1s
+		clk.Advance(999 * time.Millisecond)
+		select {
+		case <-ticks:
+			t.Fatalf("tick [%d] within 1s", i)
+		default:
+		}
+		clk.Advance(19 * time.Second)
+		<-ticks
+	}
+}
+
+// ageRecorder is a ConnSelector recording the age of the first connection on every selection
+type ageRecorder struct {
+	ages chan time.Duration
+}
+
+func (r ageRecorder) SelectConn(info v2.SelectInfo) int {
+	r.ages <- info.Conns[0].Age
+	return 0
+}
+
+func TestConnAgeOnClock(t *testing.T) {
+	clk := grpctest.NewFakeClock(time.Unix(1000, 0))
+	r := ageRecorder{ages: make(chan time.Duration, 1)}
+	cfg := v2.ClientConfigBuilder().
+		WithTarget(startServer(t)).
+		WithMinReadyConns(1).
+		WithClock(clk).
+		WithRefreshInterval(time.Hour).
+		WithConnSelector(r).
+		Build()
+	c, err := v2.NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+
+	clk.Advance(5 * time.Second)
+	if _, err := hpb.NewHealthClient(c).Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if age := <-r.ages; age != 5*time.Second {
+		t.Fatalf("expected the connection to be [%v] old as per the pool clock, got [%v]", 5*time.Second, age)
+	}
+}
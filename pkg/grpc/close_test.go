@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCloseLeavesNoGoroutines(t *testing.T) {
+	primary, dr := startServer(t), startServer(t)
+	before := runtime.NumGoroutine()
+
+	cfg := ClientConfigBuilder().
+		WithPoolSize(3).
+		WithConnMaxLifetime(50 * time.Millisecond).
+		WithEventDrivenRefresh(true).
+		WithAutoscale(AutoscaleConfig{MinSize: 2, MaxSize: 4, Interval: 10 * time.Millisecond}).
+		WithTargetTiers(TierConfig{
+			Tiers:    [][]Target{{{Addr: primary}}, {{Addr: dr}}},
+			Interval: 10 * time.Millisecond,
+		}).
+		WithRetryPolicy(RetryPolicy{}).
+		Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hc := hpb.NewHealthClient(c)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := hc.Watch(ctx, &hpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	// let the connections outlive their lifetime, so that they get refreshed while the stream is open
+	for i := 0; i < 10; i++ {
+		if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the server side goroutines of the closed connections exit asynchronously
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<20)
+			n := runtime.Stack(buf, true)
+			t.Fatalf("[%d] goroutines before the client, [%d] after closing it\n%s", before, runtime.NumGoroutine(), buf[:n])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
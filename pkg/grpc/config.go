@@ -12,6 +12,26 @@ type ClientConfig struct {
 	connectionPoolSize          int
 	connectionMaxLifeTime       time.Duration
 	connectionLifeTimeDeviation time.Duration
+	refreshMode                 RefreshMode
+	connectionDrainTimeout      time.Duration
+	connectionWarmupTimeout     time.Duration
+	selector                    ConnSelector
+	autoscale                   *AutoscaleConfig
+	minReadyConns               int
+	readyTimeout                time.Duration
+	refreshInterval             time.Duration
+	eventDrivenRefresh          bool
+	refreshRateLimit            time.Duration
+	clock                       Clock
+	lifetimePolicy              LifetimePolicy
+	connectionMaxRequests       int64
+	connectionMaxBytes          int64
+	distinctBackendAttempts     int
+	targets                     []Target
+	targetTiers                 *TierConfig
+	retryPolicy                 *RetryPolicy
+	hedgePolicy                 *HedgePolicy
+	outlierEjection             *OutlierConfig
 }
 
 type clientConfigBuilder struct {
@@ -22,6 +42,26 @@ type clientConfigBuilder struct {
 	poolSize        int
 	connMaxLifetime time.Duration
 	stdDev          time.Duration
+	refreshMode     RefreshMode
+	drainTimeout    time.Duration
+	warmupTimeout   time.Duration
+	selector        ConnSelector
+	autoscale       *AutoscaleConfig
+	minReadyConns   int
+	readyTimeout    time.Duration
+	refreshInterval time.Duration
+	eventDriven     bool
+	rateLimit       time.Duration
+	clock           Clock
+	lifetimePolicy  LifetimePolicy
+	maxRequests     int64
+	maxBytes        int64
+	distinctBackend int
+	targets         []Target
+	targetTiers     *TierConfig
+	retryPolicy     *RetryPolicy
+	hedgePolicy     *HedgePolicy
+	outlier         *OutlierConfig
 	//grpcDialer      Dialer
 }
 
@@ -64,6 +104,119 @@ func (b *clientConfigBuilder) WithStdDeviation(d time.Duration) *clientConfigBui
 	return b
 }
 
+func (b *clientConfigBuilder) WithRefreshMode(mode RefreshMode) *clientConfigBuilder {
+	b.refreshMode = mode
+	return b
+}
+
+func (b *clientConfigBuilder) WithConnDrainTimeout(d time.Duration) *clientConfigBuilder {
+	b.drainTimeout = d
+	return b
+}
+
+func (b *clientConfigBuilder) WithConnWarmupTimeout(d time.Duration) *clientConfigBuilder {
+	b.warmupTimeout = d
+	return b
+}
+
+func (b *clientConfigBuilder) WithSelector(s Selector) *clientConfigBuilder {
+	b.selector = AdaptSelector(s)
+	return b
+}
+
+func (b *clientConfigBuilder) WithConnSelector(s ConnSelector) *clientConfigBuilder {
+	b.selector = s
+	return b
+}
+
+func (b *clientConfigBuilder) WithAutoscale(cfg AutoscaleConfig) *clientConfigBuilder {
+	b.autoscale = &cfg
+	return b
+}
+
+func (b *clientConfigBuilder) WithMinReadyConns(n int) *clientConfigBuilder {
+	b.minReadyConns = n
+	return b
+}
+
+func (b *clientConfigBuilder) WithReadyTimeout(d time.Duration) *clientConfigBuilder {
+	b.readyTimeout = d
+	return b
+}
+
+func (b *clientConfigBuilder) WithRefreshInterval(d time.Duration) *clientConfigBuilder {
+	b.refreshInterval = d
+	return b
+}
+
+func (b *clientConfigBuilder) WithEventDrivenRefresh(enabled bool) *clientConfigBuilder {
+	b.eventDriven = enabled
+	return b
+}
+
+func (b *clientConfigBuilder) WithRefreshRateLimit(d time.Duration) *clientConfigBuilder {
+	b.rateLimit = d
+	return b
+}
+
+func (b *clientConfigBuilder) WithClock(c Clock) *clientConfigBuilder {
+	b.clock = c
+	return b
+}
+
+func (b *clientConfigBuilder) WithLifetimePolicy(p LifetimePolicy) *clientConfigBuilder {
+	b.lifetimePolicy = p
+	return b
+}
+
+func (b *clientConfigBuilder) WithConnMaxRequests(n int64) *clientConfigBuilder {
+	b.maxRequests = n
+	return b
+}
+
+func (b *clientConfigBuilder) WithConnMaxBytes(n int64) *clientConfigBuilder {
+	b.maxBytes = n
+	return b
+}
+
+func (b *clientConfigBuilder) WithDistinctBackends(maxAttempts int) *clientConfigBuilder {
+	b.distinctBackend = maxAttempts
+	return b
+}
+
+func (b *clientConfigBuilder) WithTargets(targets ...string) *clientConfigBuilder {
+	b.targets = make([]Target, 0, len(targets))
+	for _, target := range targets {
+		b.targets = append(b.targets, Target{Addr: target, Weight: 1})
+	}
+	return b
+}
+
+func (b *clientConfigBuilder) WithWeightedTargets(targets ...Target) *clientConfigBuilder {
+	b.targets = targets
+	return b
+}
+
+func (b *clientConfigBuilder) WithTargetTiers(cfg TierConfig) *clientConfigBuilder {
+	b.targetTiers = &cfg
+	return b
+}
+
+func (b *clientConfigBuilder) WithRetryPolicy(policy RetryPolicy) *clientConfigBuilder {
+	b.retryPolicy = &policy
+	return b
+}
+
+func (b *clientConfigBuilder) WithHedgePolicy(policy HedgePolicy) *clientConfigBuilder {
+	b.hedgePolicy = &policy
+	return b
+}
+
+func (b *clientConfigBuilder) WithOutlierEjection(cfg OutlierConfig) *clientConfigBuilder {
+	b.outlier = &cfg
+	return b
+}
+
 func (b *clientConfigBuilder) Build() *ClientConfig {
 	return &ClientConfig{
 		name:                        GetOrDefault[string](b.name, "grpc-v2-client"),
@@ -73,6 +226,26 @@ func (b *clientConfigBuilder) Build() *ClientConfig {
 		connectionPoolSize:          GetOrDefault[int](b.poolSize, defaultConnectionPoolSize),
 		connectionMaxLifeTime:       GetOrDefault[time.Duration](b.connMaxLifetime, defaultConnMaxTimeout),
 		connectionLifeTimeDeviation: GetOrDefault[time.Duration](b.stdDev, defaultConnStdDeviation),
+		refreshMode:                 b.refreshMode,
+		connectionDrainTimeout:      GetOrDefault[time.Duration](b.drainTimeout, defaultConnDrainTimeout),
+		connectionWarmupTimeout:     GetOrDefault[time.Duration](b.warmupTimeout, defaultConnWarmupTimeout),
+		selector:                    b.selector,
+		autoscale:                   b.autoscale,
+		minReadyConns:               b.minReadyConns,
+		readyTimeout:                GetOrDefault[time.Duration](b.readyTimeout, defaultReadyTimeout),
+		refreshInterval:             GetOrDefault[time.Duration](b.refreshInterval, defaultRefreshInterval),
+		eventDrivenRefresh:          b.eventDriven,
+		refreshRateLimit:            GetOrDefault[time.Duration](b.rateLimit, defaultRefreshRateLimit),
+		clock:                       b.clock,
+		lifetimePolicy:              b.lifetimePolicy,
+		connectionMaxRequests:       b.maxRequests,
+		connectionMaxBytes:          b.maxBytes,
+		distinctBackendAttempts:     b.distinctBackend,
+		targets:                     b.targets,
+		targetTiers:                 b.targetTiers,
+		retryPolicy:                 b.retryPolicy,
+		hedgePolicy:                 b.hedgePolicy,
+		outlierEjection:             b.outlier,
 	}
 }
 
@@ -89,3 +262,43 @@ func (c *ClientConfig) PoolSize() int { return c.connectionPoolSize }
 func (c *ClientConfig) ConnMaxLifetime() time.Duration { return c.connectionMaxLifeTime }
 
 func (c *ClientConfig) ConnLifetimeDeviation() time.Duration { return c.connectionLifeTimeDeviation }
+
+func (c *ClientConfig) RefreshMode() RefreshMode { return c.refreshMode }
+
+func (c *ClientConfig) ConnDrainTimeout() time.Duration { return c.connectionDrainTimeout }
+
+func (c *ClientConfig) ConnWarmupTimeout() time.Duration { return c.connectionWarmupTimeout }
+
+func (c *ClientConfig) Selector() ConnSelector { return c.selector }
+
+func (c *ClientConfig) Autoscale() *AutoscaleConfig { return c.autoscale }
+
+func (c *ClientConfig) MinReadyConns() int { return c.minReadyConns }
+
+func (c *ClientConfig) ReadyTimeout() time.Duration { return c.readyTimeout }
+
+func (c *ClientConfig) RefreshInterval() time.Duration { return c.refreshInterval }
+
+func (c *ClientConfig) EventDrivenRefresh() bool { return c.eventDrivenRefresh }
+
+func (c *ClientConfig) RefreshRateLimit() time.Duration { return c.refreshRateLimit }
+
+func (c *ClientConfig) Clock() Clock { return c.clock }
+
+func (c *ClientConfig) LifetimePolicy() LifetimePolicy { return c.lifetimePolicy }
+
+func (c *ClientConfig) ConnMaxRequests() int64 { return c.connectionMaxRequests }
+
+func (c *ClientConfig) ConnMaxBytes() int64 { return c.connectionMaxBytes }
+
+func (c *ClientConfig) DistinctBackendAttempts() int { return c.distinctBackendAttempts }
+
+func (c *ClientConfig) Targets() []Target { return c.targets }
+
+func (c *ClientConfig) TargetTiers() *TierConfig { return c.targetTiers }
+
+func (c *ClientConfig) RetryPolicy() *RetryPolicy { return c.retryPolicy }
+
+func (c *ClientConfig) HedgePolicy() *HedgePolicy { return c.hedgePolicy }
+
+func (c *ClientConfig) OutlierEjection() *OutlierConfig { return c.outlierEjection }
@@ -1,28 +1,177 @@
 package grpc
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 type clientConn struct {
-	conn      *grpc.ClientConn
+	conn      *sharedConn
 	createdAt time.Time
 	dl        int64 // this will be atomic value
+	removed   bool
+	target    string
+	failures  int // consecutive failures of the slot to reach its target
 	cMu       sync.Mutex
 }
 
-func wrapToClientConn(cc *grpc.ClientConn) *clientConn {
-	return &clientConn{conn: cc, createdAt: time.Now()}
+func wrapToClientConn(cc *sharedConn, target string, now time.Time) *clientConn {
+	return &clientConn{conn: cc, target: target, createdAt: now}
 }
 
 func (c *clientConn) close() error {
+	c.cMu.Lock()
+	defer c.cMu.Unlock()
+
 	return c.conn.Close()
 }
 
 func (c *clientConn) setDeadline(d time.Duration) { atomic.StoreInt64(&c.dl, int64(d)) }
 
 func (c *clientConn) deadline() time.Duration { return time.Duration(atomic.LoadInt64(&c.dl)) }
+
+// acquire returns the grpc connection currently backing this slot, holding a reference on it.
+// The caller must release the connection once the RPC made over it has finished.
+// It returns false if the slot has been removed from the pool
+func (c *clientConn) acquire() (*sharedConn, bool) {
+	c.cMu.Lock()
+	defer c.cMu.Unlock()
+
+	if c.removed {
+		return nil, false
+	}
+	c.conn.acquire()
+	return c.conn, true
+}
+
+// remove takes the slot out of service and returns the connection backing it, which should then be retired
+func (c *clientConn) remove() *sharedConn {
+	c.cMu.Lock()
+	defer c.cMu.Unlock()
+
+	c.removed = true
+	return c.conn
+}
+
+// info returns a snapshot of the connection currently backing this slot
+func (c *clientConn) info() ConnInfo {
+	c.cMu.Lock()
+	defer c.cMu.Unlock()
+
+	return ConnInfo{
+		State:     c.conn.GetState(),
+		InFlight:  c.conn.inFlight(),
+		CreatedAt: c.createdAt,
+		Lifetime:  c.deadline(),
+		Requests:  c.conn.stats.Requests(),
+		Bytes:     c.conn.stats.Bytes(),
+		Peer:      c.conn.stats.Peer(),
+		Target:    c.target,
+	}
+}
+
+// usage returns the usage of the connection currently backing this slot, it should be called with cMu held
+func (c *clientConn) usage(now time.Time) ConnUsage {
+	return ConnUsage{
+		Age:      now.Sub(c.createdAt),
+		Lifetime: c.deadline(),
+		Requests: c.conn.stats.Requests(),
+		Bytes:    c.conn.stats.Bytes(),
+	}
+}
+
+// current returns the connection currently backing this slot, or false if the slot has been removed from the pool
+func (c *clientConn) current() (*sharedConn, bool) {
+	c.cMu.Lock()
+	defer c.cMu.Unlock()
+
+	return c.conn, !c.removed
+}
+
+// connect makes the connection currently backing this slot exit IDLE state and start connecting
+func (c *clientConn) connect() {
+	c.cMu.Lock()
+	defer c.cMu.Unlock()
+
+	c.conn.Connect()
+}
+
+// sharedConn is a grpc connection shared by all the RPCs served from a clientConn slot.
+// Once retired, it is drained as soon as the last RPC holding it is released
+type sharedConn struct {
+	*grpc.ClientConn
+	// ctx is done once the connection is retired or the pool is closed
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stats     *connStats
+	outlier   outlierState
+	latency   latencyEWMA
+	refs      int64
+	_retired  uint32
+	drained   chan struct{}
+	drainOnce sync.Once
+	closeOnce sync.Once
+}
+
+func newSharedConn(ctx context.Context, cc *grpc.ClientConn, stats *connStats) *sharedConn {
+	s := &sharedConn{ClientConn: cc, stats: stats, drained: make(chan struct{})}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	return s
+}
+
+func (s *sharedConn) acquire() { atomic.AddInt64(&s.refs, 1) }
+
+func (s *sharedConn) release() {
+	if atomic.AddInt64(&s.refs, -1) == 0 && s.retired() {
+		s.markDrained()
+	}
+}
+
+func (s *sharedConn) inFlight() int64 { return atomic.LoadInt64(&s.refs) }
+
+// retire marks the connection as no longer in use by the pool, the drained channel gets closed
+// once there are no RPCs in-flight over it
+func (s *sharedConn) retire() {
+	s.cancel()
+	atomic.StoreUint32(&s._retired, 1)
+	if atomic.LoadInt64(&s.refs) == 0 {
+		s.markDrained()
+	}
+}
+
+func (s *sharedConn) markDrained() {
+	s.drainOnce.Do(func() {
+		close(s.drained)
+	})
+}
+
+func (s *sharedConn) retired() bool { return atomic.LoadUint32(&s._retired) == 1 }
+
+// Close closes the underlying grpc connection, only the first call has any effect
+func (s *sharedConn) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.cancel()
+		err = s.ClientConn.Close()
+	})
+	return err
+}
+
+// waitForReady connects the grpc connection and blocks till it reaches READY state or ctx is done
+func waitForReady(ctx context.Context, cc *grpc.ClientConn) error {
+	cc.Connect()
+	for {
+		state := cc.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !cc.WaitForStateChange(ctx, state) {
+			return ctx.Err()
+		}
+	}
+}
@@ -13,6 +13,23 @@ const (
 	defaultConnStdDeviation = 30 * time.Second
 )
 
+const (
+	defaultConnWarmupTimeout = 10 * time.Second
+	defaultConnDrainTimeout  = 30 * time.Second
+	defaultReadyTimeout      = 30 * time.Second
+)
+
+const (
+	defaultRefreshInterval  = 30 * time.Second
+	defaultRefreshRateLimit = time.Second
+	defaultRefreshJitter    = 0.1
+	maxSchedulerJitter      = 0.9
+)
+
+const (
+	targetFailoverThreshold = 3
+)
+
 var (
 	maxDuration = (time.Unix(1<<63-62135596801, 999999999)).Sub(time.Now())
 )
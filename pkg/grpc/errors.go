@@ -2,17 +2,38 @@ package grpc
 
 import (
 	"errors"
+	"fmt"
 )
 
 var (
-	grpcDialErr         = errors.New("go-grpc:error while grpc dial to the server")
-	asyncRefreshInitErr = errors.New("go-grpc:error while initializing background connection refresh job")
-	connRefreshErr      = errors.New("go-grpc:error while refreshing connection")
-	cronErr             = errors.New("go-grpc:error in initializing cron job")
-	clientInitErr       = errors.New("go-grpc:error while initializing client")
+	ErrDial             = errors.New("go-grpc:error while grpc dial to the server")
+	ErrAsyncRefreshInit = errors.New("go-grpc:error while initializing background connection refresh job")
+	ErrConnRefresh      = errors.New("go-grpc:error while refreshing connection")
+	ErrClientInit       = errors.New("go-grpc:error while initializing client")
+	ErrConnWarmup       = errors.New("go-grpc:error while warming up connection")
+	ErrPoolClose        = errors.New("go-grpc:error while waiting for the connection pool to close")
 )
 
 var (
-	noHealthyConnAvailableErr = errors.New("go-grpc:error no healthy connection available")
-	connPoolCloseErr          = errors.New("go-grpc:error connection pool is already closed")
+	ErrNoHealthyConn   = errors.New("go-grpc:error no healthy connection available")
+	ErrNoConnAvailable = errors.New("go-grpc:error no connection available in the pool")
+	ErrPoolClosed      = errors.New("go-grpc:error connection pool is already closed")
+	ErrInvalidPoolSize = errors.New("go-grpc:error pool size should be greater than 0")
+	ErrNotReady        = errors.New("go-grpc:error min no of connections not ready")
+	ErrInvalidSplit    = errors.New("go-grpc:error invalid traffic split")
+	ErrInvalidShadow   = errors.New("go-grpc:error invalid traffic shadow")
 )
+
+// DialError is returned when the grpc dial to a target fails. It matches ErrDial with errors.Is
+type DialError struct {
+	Target string
+	Err    error
+}
+
+func (e *DialError) Error() string {
+	return fmt.Sprintf("[%s] to target [%s], error is: [%s]", ErrDial, e.Target, e.Err)
+}
+
+func (e *DialError) Unwrap() error { return e.Err }
+
+func (e *DialError) Is(target error) bool { return target == ErrDial }
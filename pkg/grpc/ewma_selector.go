@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	slowStartMinShare = 0.1
+)
+
+// EWMASelector implements ConnSelector interface to favour the connections with the lower moving average of the RPC latency.
+// The moving average is of the successful unary RPCs only, as the lifetime of a stream says nothing of the latency of the connection.
+// It picks 2 healthy connections at random and selects the one with the lower latency weighted by its RPCs in-flight.
+// A connection younger than the slow start period is penalised in proportion to its age, so that a freshly refreshed
+// connection ramps up to its share of the RPCs instead of getting a burst of them
+type EWMASelector struct {
+	slowStart time.Duration
+	rnd       *rand.Rand
+	mu        sync.Mutex
+}
+
+// NewEWMASelector returns an EWMASelector with the slow start period, 0 turns off the slow start
+func NewEWMASelector(slowStart time.Duration) *EWMASelector {
+	return &EWMASelector{
+		slowStart: slowStart,
+		rnd:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (s *EWMASelector) SelectConn(info SelectInfo) int {
+	conns := info.Conns
+	candidates := make([]int, 0, len(conns))
+	for i, c := range conns {
+		if c.Healthy {
+			candidates = append(candidates, i)
+		}
+	}
+	// no healthy connection, let the pool deal with it
+	if len(candidates) == 0 {
+		for i := range conns {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0]
+	}
+
+	s.mu.Lock()
+	i := s.rnd.Intn(len(candidates))
+	j := s.rnd.Intn(len(candidates) - 1)
+	s.mu.Unlock()
+
+	// pick j from all the candidates except i
+	if j >= i {
+		j++
+	}
+	i, j = candidates[i], candidates[j]
+
+	// connections which have not served an RPC yet are assumed to be as fast as the others on average
+	avg := averageLatency(conns)
+	if s.cost(conns[j], avg) < s.cost(conns[i], avg) {
+		return j
+	}
+	return i
+}
+
+func (s *EWMASelector) cost(c ConnInfo, avg time.Duration) float64 {
+	latency := c.Latency
+	if latency <= 0 {
+		latency = avg
+	}
+	cost := float64(latency) * float64(c.InFlight+1)
+
+	if s.slowStart > 0 && c.Age < s.slowStart {
+		share := float64(c.Age) / float64(s.slowStart)
+		if share < slowStartMinShare {
+			share = slowStartMinShare
+		}
+		cost /= share
+	}
+	return cost
+}
+
+func averageLatency(conns []ConnInfo) time.Duration {
+	total, n := time.Duration(0), 0
+	for _, c := range conns {
+		if c.Latency > 0 {
+			total += c.Latency
+			n++
+		}
+	}
+	if n == 0 {
+		return 1
+	}
+	return total / time.Duration(n)
+}
@@ -0,0 +1,42 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEWMASelectorSlowStart(t *testing.T) {
+	s := NewEWMASelector(10 * time.Second)
+	info := SelectInfo{Conns: []ConnInfo{
+		{Healthy: true, Latency: time.Millisecond, Age: time.Hour},
+		{Healthy: true, Latency: time.Millisecond, Age: 0},
+	}}
+	for i := 0; i < 100; i++ {
+		if idx := s.SelectConn(info); idx != 0 {
+			t.Fatalf("selected the connection within its slow start over an equally fast one")
+		}
+	}
+
+	// once out of its slow start, the connection competes on its latency alone
+	info.Conns[1].Age = 10 * time.Second
+	info.Conns[1].Latency = time.Microsecond
+	for i := 0; i < 100; i++ {
+		if idx := s.SelectConn(info); idx != 1 {
+			t.Fatalf("selected the slower connection")
+		}
+	}
+}
+
+func TestEWMASelectorSkipsUnhealthyConns(t *testing.T) {
+	s := NewEWMASelector(0)
+	info := SelectInfo{Conns: []ConnInfo{
+		{Healthy: true, Latency: time.Second},
+		{Healthy: false, Latency: time.Microsecond},
+		{Healthy: true, Latency: time.Second},
+	}}
+	for i := 0; i < 100; i++ {
+		if idx := s.SelectConn(info); idx == 1 {
+			t.Fatalf("selected the unhealthy connection")
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package grpc
+
+import (
+	"context"
+	"time"
+)
+
+// ConnHealth reports for every connection of the pool backing c, if it is healthy and if it is due for a refresh
+func ConnHealth(c Client) (healthy, refresh []bool) {
+	pool := c.(*client).pool
+	for _, conn := range pool.snapshot() {
+		healthy = append(healthy, pool.isHealthyConn(conn))
+		refresh = append(refresh, pool.shouldRefresh(conn))
+	}
+	return healthy, refresh
+}
+
+// RunScheduler runs job with a scheduler ticking every interval on clk, till ctx is done
+func RunScheduler(ctx context.Context, interval time.Duration, jitter float64, clk Clock, job func()) {
+	newScheduler(interval, jitter, clk).run(ctx, job)
+}
@@ -2,13 +2,28 @@ package grpc
 
 import (
 	"context"
+	"fmt"
 
 	"google.golang.org/grpc"
 )
 
 type Client interface {
 	grpc.ClientConnInterface
-	Close()
+	// Close closes all the connections and waits till all the background goroutines of the client have exited
+	Close(ctx context.Context) error
+}
+
+// Resizer is implemented by the clients, the connection pool of which can be resized at runtime,
+// e.g. the one returned by NewClient
+type Resizer interface {
+	// Resize grows or shrinks the connection pool to n connections
+	Resize(n int) error
+}
+
+// PeerReporter is implemented by the clients, which report the backends their pooled connections have landed on
+type PeerReporter interface {
+	// PeerDistribution returns the no of pooled connections landed on every backend, keyed on the remote address
+	PeerDistribution() map[string]int
 }
 
 type client struct {
@@ -26,7 +41,7 @@ func NewClient(cfg *ClientConfig, opts ...grpc.DialOption) (Client, error) {
 
 	pool, err := newConnPool(cfg.target, getPoolOptions(cfg, opts)...)
 	if err != nil {
-		panic(clientInitErr)
+		return nil, fmt.Errorf("[%w], error is: [%w]", ErrClientInit, err)
 	}
 	return &client{
 		pool: pool,
@@ -41,4 +56,8 @@ func (c client) NewStream(ctx context.Context, desc *grpc.StreamDesc, method str
 	return c.pool.NewStream(ctx, desc, method, opts...)
 }
 
-func (c client) Close() { _ = c.pool.Close() }
+func (c client) Close(ctx context.Context) error { return c.pool.Close(ctx) }
+
+func (c client) Resize(n int) error { return c.pool.Resize(n) }
+
+func (c client) PeerDistribution() map[string]int { return c.pool.PeerDistribution() }
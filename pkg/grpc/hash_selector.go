@@ -0,0 +1,132 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	defaultHashReplicas = 100
+)
+
+// HashKeyFunc extracts the key an RPC is routed on from the call context.
+// It returns false if the RPC carries no key
+type HashKeyFunc func(ctx context.Context) (string, bool)
+
+// MetadataHashKey returns a HashKeyFunc which reads the key from the outgoing grpc metadata of the RPC
+func MetadataHashKey(key string) HashKeyFunc {
+	return func(ctx context.Context) (string, bool) {
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if !ok {
+			return "", false
+		}
+		if v := md.Get(key); len(v) > 0 && v[0] != "" {
+			return v[0], true
+		}
+		return "", false
+	}
+}
+
+// ContextValueHashKey returns a HashKeyFunc which reads the key from the value stored in the context against key.
+// The value should either be a string or a fmt.Stringer
+func ContextValueHashKey(key any) HashKeyFunc {
+	return func(ctx context.Context) (string, bool) {
+		switch v := ctx.Value(key).(type) {
+		case string:
+			return v, v != ""
+		case fmt.Stringer:
+			return v.String(), true
+		}
+		return "", false
+	}
+}
+
+// ConsistentHashSelector implements ConnSelector interface to route all the RPCs carrying the same key
+// to the same connection in the pool. Each connection is placed on a hash ring with virtual nodes,
+// so only a small share of the keys gets remapped when the pool size changes.
+// A refresh of a connection keeps its place on the ring.
+// RPCs without a key, are served in a round-robin manner
+type ConsistentHashSelector struct {
+	keyFn    HashKeyFunc
+	replicas int
+	fallback ConnSelector
+	ring     []ringNode
+	ringSize int
+	mu       sync.Mutex
+}
+
+type ringNode struct {
+	hash uint64
+	idx  int
+}
+
+// NewConsistentHashSelector creates a ConsistentHashSelector placing every connection replicas times on the hash ring
+func NewConsistentHashSelector(keyFn HashKeyFunc, replicas int) *ConsistentHashSelector {
+	return &ConsistentHashSelector{
+		keyFn:    keyFn,
+		replicas: GetOrDefault[int](replicas, defaultHashReplicas),
+		fallback: AdaptSelector(&RoundRobinSelector{}),
+	}
+}
+
+func (s *ConsistentHashSelector) SelectConn(info SelectInfo) int {
+	key, ok := s.keyFn(info.Ctx)
+	if !ok {
+		return s.fallback.SelectConn(info)
+	}
+
+	ring := s.ringFor(len(info.Conns))
+	h := hashOf(key)
+	start := sort.Search(len(ring), func(i int) bool { return ring[i].hash >= h })
+
+	// walk the ring clockwise till a healthy connection is found
+	for i := 0; i < len(ring); i++ {
+		node := ring[(start+i)%len(ring)]
+		if info.Conns[node.idx].Healthy {
+			return node.idx
+		}
+	}
+	return ring[start%len(ring)].idx
+}
+
+// ringFor returns the hash ring for a pool of size n, the ring is rebuilt only when the pool size changes
+func (s *ConsistentHashSelector) ringFor(n int) []ringNode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ringSize == n && s.ring != nil {
+		return s.ring
+	}
+
+	ring := make([]ringNode, 0, n*s.replicas)
+	for idx := 0; idx < n; idx++ {
+		for r := 0; r < s.replicas; r++ {
+			ring = append(ring, ringNode{hash: hashOf(strconv.Itoa(idx) + "#" + strconv.Itoa(r)), idx: idx})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+
+	s.ring = ring
+	s.ringSize = n
+	return ring
+}
+
+// hashOf hashes the key with fnv-1a, followed by a finalizer to spread similar keys evenly over the ring
+func hashOf(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
@@ -0,0 +1,60 @@
+package grpc
+
+import (
+	"time"
+)
+
+const (
+	healInitialBackoff = 500 * time.Millisecond
+	healMaxBackoff     = 30 * time.Second
+)
+
+// missing returns the no of connections the pool is short of its target size
+func (pool *clientConnPool) missing() int {
+	pool.connsMu.Lock()
+	defer pool.connsMu.Unlock()
+
+	return pool.size - len(pool.conns)
+}
+
+// triggerHeal wakes up the healer to re-dial the missing connections of the pool
+func (pool *clientConnPool) triggerHeal() {
+	select {
+	case pool.healCh <- struct{}{}:
+	default:
+	}
+}
+
+// heal re-dials the connections that failed to dial, till the pool is back to its target size.
+// Failed attempts are retried with an exponential backoff
+func (pool *clientConnPool) heal() {
+	for {
+		select {
+		case <-pool.ctx.Done():
+			return
+		case <-pool.healCh:
+		}
+
+		backoff := healInitialBackoff
+		for pool.healOnce() != nil {
+			if !pool.wait(backoff) {
+				return
+			}
+			backoff *= 2
+			if backoff > healMaxBackoff {
+				backoff = healMaxBackoff
+			}
+		}
+	}
+}
+
+// healOnce dials all the missing connections of the pool
+func (pool *clientConnPool) healOnce() error {
+	pool.resizeMu.Lock()
+	defer pool.resizeMu.Unlock()
+
+	if n := pool.missing(); n > 0 {
+		return pool.grow(n)
+	}
+	return nil
+}
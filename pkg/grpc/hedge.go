@@ -0,0 +1,179 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultHedgeDelay       = 50 * time.Millisecond
+	hedgeLatencyWindow      = 128
+	hedgeMinLatencySamples  = 20
+	hedgePercentileRecalcAt = 16
+)
+
+// HedgePolicy configures the pool to send a second copy of the unary RPCs of the hedged methods on a different
+// connection of the pool, if the first one has not answered within a delay. The first reply wins and the other
+// RPC is cancelled. Only idempotent methods should be hedged, and call options which write into the caller's
+// variables, e.g. grpc.Header, should not be used with them as both the copies share the call options.
+// Only the RPCs the reply of which is a proto.Message are hedged, as the reply of the winner is copied into it,
+// the other RPCs of the hedged methods are served without a hedge
+type HedgePolicy struct {
+	// Methods are the full method names of the hedged RPCs, e.g. /package.Service/Method
+	Methods []string
+	// Delay after which the hedge is sent, if the first RPC has not answered
+	Delay time.Duration
+	// Percentile, between 0 and 1, of the recent latencies of a method after which the hedge is sent.
+	// Delay is used till enough latencies of the method are recorded
+	Percentile float64
+}
+
+// WithHedging turns on the hedging of the unary RPCs of the hedged methods
+func WithHedging(policy HedgePolicy) Option {
+	return optionFunc(func(o *options) {
+		o.hedgePolicy = &policy
+	})
+}
+
+// hedger decides on the hedging delay of every hedged method, from its recent latencies
+type hedger struct {
+	policy    HedgePolicy
+	latencies map[string]*latencyWindow
+}
+
+func newHedger(policy HedgePolicy) *hedger {
+	policy.Delay = GetOrDefault[time.Duration](policy.Delay, defaultHedgeDelay)
+	h := &hedger{policy: policy, latencies: make(map[string]*latencyWindow, len(policy.Methods))}
+	for _, m := range policy.Methods {
+		h.latencies[m] = &latencyWindow{}
+	}
+	return h
+}
+
+func (h *hedger) hedged(method string) bool {
+	_, ok := h.latencies[method]
+	return ok
+}
+
+func (h *hedger) delay(method string) time.Duration {
+	if h.policy.Percentile <= 0 || h.policy.Percentile > 1 {
+		return h.policy.Delay
+	}
+	if d, ok := h.latencies[method].percentile(h.policy.Percentile); ok {
+		return d
+	}
+	return h.policy.Delay
+}
+
+func (h *hedger) record(method string, latency time.Duration) { h.latencies[method].record(latency) }
+
+// latencyWindow keeps the latest latencies of a method, the percentile of which is recalculated every few samples
+type latencyWindow struct {
+	samples [hedgeLatencyWindow]time.Duration
+	n       int
+	sorted  []time.Duration
+	stale   int
+	mu      sync.Mutex
+}
+
+func (w *latencyWindow) record(latency time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.samples[w.n%hedgeLatencyWindow] = latency
+	w.n++
+	w.stale++
+}
+
+func (w *latencyWindow) percentile(p float64) (time.Duration, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.n < hedgeMinLatencySamples {
+		return 0, false
+	}
+	if w.sorted == nil || w.stale >= hedgePercentileRecalcAt {
+		size := w.n
+		if size > hedgeLatencyWindow {
+			size = hedgeLatencyWindow
+		}
+		w.sorted = append(w.sorted[:0], w.samples[:size]...)
+		sort.Slice(w.sorted, func(i, j int) bool { return w.sorted[i] < w.sorted[j] })
+		w.stale = 0
+	}
+	idx := int(math.Ceil(p*float64(len(w.sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return w.sorted[idx], true
+}
+
+type hedgeResult struct {
+	cc    *sharedConn
+	reply proto.Message
+	err   error
+}
+
+// invokeHedged serves the RPC from a connection picked by the selector, and sends a hedge on another connection picked
+// by the selector if the RPC has not answered within the hedging delay. The reply of the first successful RPC is copied
+// into reply, and the other one is cancelled. It returns the connection which served the reply
+func (pool *clientConnPool) invokeHedged(ctx context.Context, method string, args any, reply proto.Message, opts []grpc.CallOption) (*sharedConn, error) {
+	cc, err := pool.acquire(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	// cancels the RPC which loses
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	start := pool.opts.clock.Now()
+	results := make(chan hedgeResult, 2)
+	attempt := func(cc *sharedConn) {
+		r := reply.ProtoReflect().New().Interface()
+		err := pool.invokeConn(ctx, cc, method, args, r, opts)
+		results <- hedgeResult{cc: cc, reply: r, err: err}
+	}
+	go attempt(cc)
+
+	t := pool.opts.clock.NewTimer(pool.hedger.delay(method))
+	defer t.Stop()
+	hedge, pending := t.C(), 1
+	for {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				pool.hedger.record(method, pool.opts.clock.Now().Sub(start))
+				proto.Reset(reply)
+				proto.Merge(reply, r.reply)
+				return r.cc, nil
+			}
+			// no other RPC in-flight, or the hedge was not sent yet
+			if pending == 0 {
+				return r.cc, r.err
+			}
+		case <-hedge:
+			hedge = nil
+			// the connections the previous attempts of a retried RPC failed on stay excluded
+			excluded := append(excludedConns{cc}, excludedConnsFrom(ctx)...)
+			other, err := pool.acquire(withExcludedConns(ctx, excluded), method)
+			if err != nil {
+				continue
+			}
+			// the pool has no other healthy connection to hedge on
+			if excluded.includes(other) {
+				other.release()
+				continue
+			}
+			pending++
+			go attempt(other)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+func TestHedgeServedByFastConn(t *testing.T) {
+	cfg := ClientConfigBuilder().
+		WithTargets(startSlowServer(t, time.Second), startSlowServer(t, time.Millisecond)).
+		WithPoolSize(2).
+		WithMinReadyConns(2).
+		WithHedgePolicy(HedgePolicy{Methods: []string{healthCheckMethod}, Delay: 20 * time.Millisecond}).
+		Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+
+	// half the RPCs go to the slow server first, and are answered by their hedge to the fast one
+	hc := hpb.NewHealthClient(c)
+	for i := 0; i < 10; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		r, err := hc.Check(ctx, &hpb.HealthCheckRequest{})
+		cancel()
+		if err != nil {
+			t.Fatalf("RPC [%d] failed, error is: [%v]", i, err)
+		}
+		if r.Status != hpb.HealthCheckResponse_SERVING {
+			t.Fatalf("RPC [%d] got reply [%v]", i, r)
+		}
+	}
+}
+
+func TestHedgeSkipsConnsRetryFailedOn(t *testing.T) {
+	var failed int64
+	cfg := ClientConfigBuilder().
+		WithTargets(startCountingFailingServer(t, &failed), startSlowServer(t, 100*time.Millisecond)).
+		WithPoolSize(2).
+		WithMinReadyConns(2).
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, BudgetTokens: 100}).
+		WithHedgePolicy(HedgePolicy{Methods: []string{healthCheckMethod}, Delay: 20 * time.Millisecond}).
+		Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+
+	// an RPC hits the failing server at most once, either on its first attempt or on its hedge,
+	// as the hedge of the retry does not go back to the connection the first attempt failed on
+	hc := hpb.NewHealthClient(c)
+	const rpcs = 10
+	for i := 0; i < rpcs; i++ {
+		if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+			t.Fatalf("RPC [%d] failed, error is: [%v]", i, err)
+		}
+	}
+	if n := atomic.LoadInt64(&failed); n > rpcs {
+		t.Fatalf("failing server got [%d] RPCs out of [%d]", n, rpcs)
+	}
+}
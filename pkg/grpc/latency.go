@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	latencyEWMAAlpha = 0.3
+	latencyDecay     = 10 * time.Second
+)
+
+// latencyEWMA is an exponentially weighted moving average of the RPC latency of a connection.
+// It decays towards 0 while the connection serves no RPCs, so that a connection which was slow once gets retried
+type latencyEWMA struct {
+	value float64
+	at    time.Time
+	mu    sync.Mutex
+}
+
+func (l *latencyEWMA) record(latency time.Duration, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.at.IsZero() {
+		l.value = float64(latency)
+	} else {
+		prev := l.decayed(now)
+		l.value = prev + latencyEWMAAlpha*(float64(latency)-prev)
+	}
+	l.at = now
+}
+
+// get returns the moving average at now, 0 if no latency has been recorded yet
+func (l *latencyEWMA) get(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.at.IsZero() {
+		return 0
+	}
+	return time.Duration(l.decayed(now))
+}
+
+func (l *latencyEWMA) decayed(now time.Time) float64 {
+	idle := now.Sub(l.at)
+	if idle <= 0 {
+		return l.value
+	}
+	return l.value * math.Exp(-float64(idle)/float64(latencyDecay))
+}
@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newTestClient(t *testing.T, target string) *clientConnPool {
+	t.Helper()
+
+	cfg := ClientConfigBuilder().WithTarget(target).WithPoolSize(1).WithMinReadyConns(1).Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close(context.Background()) })
+	return c.(*client).pool
+}
+
+func connLatency(pool *clientConnPool) int64 {
+	conn, _ := pool.snapshot()[0].current()
+	return int64(conn.latency.get(pool.opts.clock.Now()))
+}
+
+func TestLatencyRecordedForSuccessfulUnaryRPCs(t *testing.T) {
+	pool := newTestClient(t, startServer(t))
+	hc := hpb.NewHealthClient(pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := hc.Watch(ctx, &hpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	stream.Recv()
+	// the stream releases the connection once it finishes
+	conn, _ := pool.snapshot()[0].current()
+	for deadline := time.Now().Add(time.Second); conn.inFlight() > 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if l := connLatency(pool); l != 0 {
+		t.Fatalf("latency [%d] recorded for a stream", l)
+	}
+
+	if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if l := connLatency(pool); l <= 0 {
+		t.Fatal("latency not recorded for a successful RPC")
+	}
+}
+
+func TestLatencyNotRecordedForFailedRPCs(t *testing.T) {
+	pool := newTestClient(t, startFailingServer(t))
+	hc := hpb.NewHealthClient(pool)
+
+	for i := 0; i < 3; i++ {
+		if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err == nil {
+			t.Fatal("RPC succeeded on a failing server")
+		}
+	}
+	if l := connLatency(pool); l != 0 {
+		t.Fatalf("latency [%d] recorded for failed RPCs", l)
+	}
+}
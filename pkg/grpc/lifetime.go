@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// LifetimePolicy decides for how long a connection in the pool is used, before it gets refreshed
+type LifetimePolicy interface {
+	// Lifetime returns the lifetime of a newly dialled connection. rnd is the random source of the pool,
+	// and should not be used once Lifetime returns
+	Lifetime(rnd *rand.Rand) time.Duration
+	// Expired reports whether a connection has reached the end of its life
+	Expired(usage ConnUsage) bool
+}
+
+// ConnUsage is the usage of a connection since it was dialled
+type ConnUsage struct {
+	Age      time.Duration
+	Lifetime time.Duration
+	Requests int64
+	Bytes    int64
+}
+
+// WithLifetimePolicy overrides the LifetimePolicy of the connections in the pool
+func WithLifetimePolicy(p LifetimePolicy) Option {
+	return optionFunc(func(o *options) {
+		o.lifetimePolicy = p
+	})
+}
+
+type fixedLifetime struct {
+	d time.Duration
+}
+
+// FixedLifetime expires every connection after d
+func FixedLifetime(d time.Duration) LifetimePolicy { return fixedLifetime{d: d} }
+
+func (p fixedLifetime) Lifetime(*rand.Rand) time.Duration { return p.d }
+
+func (p fixedLifetime) Expired(u ConnUsage) bool { return u.Age >= u.Lifetime }
+
+type uniformJitterLifetime struct {
+	base   time.Duration
+	stdDev time.Duration
+}
+
+// UniformJitterLifetime expires every connection after base plus a uniformly distributed jitter of [0.5, 1.5) * stdDev.
+// This is the default policy, with the connection max lifetime and standard deviation of the pool
+func UniformJitterLifetime(base, stdDev time.Duration) LifetimePolicy {
+	return uniformJitterLifetime{base: base, stdDev: stdDev}
+}
+
+func (p uniformJitterLifetime) Lifetime(rnd *rand.Rand) time.Duration {
+	return toDuration(float64(p.base) + (rnd.Float64()+0.5)*float64(p.stdDev))
+}
+
+func (p uniformJitterLifetime) Expired(u ConnUsage) bool { return u.Age >= u.Lifetime }
+
+type gaussianLifetime struct {
+	mean   time.Duration
+	stdDev time.Duration
+}
+
+// GaussianLifetime expires every connection after a normally distributed lifetime around mean, never below 0
+func GaussianLifetime(mean, stdDev time.Duration) LifetimePolicy {
+	return gaussianLifetime{mean: mean, stdDev: stdDev}
+}
+
+func (p gaussianLifetime) Lifetime(rnd *rand.Rand) time.Duration {
+	return toDuration(float64(p.mean) + rnd.NormFloat64()*float64(p.stdDev))
+}
+
+func (p gaussianLifetime) Expired(u ConnUsage) bool { return u.Age >= u.Lifetime }
+
+type maxRequestsLifetime struct {
+	n int64
+}
+
+// MaxRequestsLifetime expires every connection once n RPCs have been completed over it
+func MaxRequestsLifetime(n int64) LifetimePolicy { return maxRequestsLifetime{n: n} }
+
+func (p maxRequestsLifetime) Lifetime(*rand.Rand) time.Duration { return maxDuration }
+
+func (p maxRequestsLifetime) Expired(u ConnUsage) bool { return u.Requests >= p.n }
+
+type maxBytesLifetime struct {
+	n int64
+}
+
+// MaxBytesLifetime expires every connection once n bytes have been sent and received over it
+func MaxBytesLifetime(n int64) LifetimePolicy { return maxBytesLifetime{n: n} }
+
+func (p maxBytesLifetime) Lifetime(*rand.Rand) time.Duration { return maxDuration }
+
+func (p maxBytesLifetime) Expired(u ConnUsage) bool { return u.Bytes >= p.n }
+
+// budgetLifetime expires a connection as per the wrapped policy, or once it has used up its request or byte budget
+type budgetLifetime struct {
+	LifetimePolicy
+	maxRequests int64
+	maxBytes    int64
+}
+
+func (p budgetLifetime) Expired(u ConnUsage) bool {
+	return p.LifetimePolicy.Expired(u) ||
+		(p.maxRequests > 0 && u.Requests >= p.maxRequests) ||
+		(p.maxBytes > 0 && u.Bytes >= p.maxBytes)
+}
+
+type neverExpire struct{}
+
+// NeverExpire never expires a connection, it is refreshed only when it is in an unhealthy state
+func NeverExpire() LifetimePolicy { return neverExpire{} }
+
+func (neverExpire) Lifetime(*rand.Rand) time.Duration { return maxDuration }
+
+func (neverExpire) Expired(ConnUsage) bool { return false }
+
+// toDuration converts f to a duration, limited to the range [0, maxDuration]
+func toDuration(f float64) time.Duration {
+	if f <= 0 {
+		return 0
+	}
+	if f >= float64(math.MaxInt64) {
+		return maxDuration
+	}
+	return time.Duration(f)
+}
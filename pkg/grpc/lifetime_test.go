@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestUsageExpiryRefreshesRightAway(t *testing.T) {
+	builders := map[string]*clientConfigBuilder{
+		"lifetime policy": ClientConfigBuilder().WithLifetimePolicy(MaxRequestsLifetime(3)),
+		"request budget":  ClientConfigBuilder().WithLifetimePolicy(NeverExpire()).WithConnMaxRequests(3),
+	}
+	for name, b := range builders {
+		t.Run(name, func(t *testing.T) {
+			// the background refresh never fires, so only the usage of the connection can refresh it
+			cfg := b.WithTarget(startServer(t)).WithMinReadyConns(1).WithRefreshInterval(time.Hour).Build()
+			c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close(context.Background())
+			pool := c.(*client).pool
+			first, _ := pool.snapshot()[0].current()
+
+			hc := hpb.NewHealthClient(c)
+			for i := 0; i < 3; i++ {
+				if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+				if curr, _ := pool.snapshot()[0].current(); curr != first {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("connection not refreshed once its usage expired it")
+				}
+			}
+		})
+	}
+}
+
+func TestBudgetLifetimeWrapsPolicy(t *testing.T) {
+	p := budgetLifetime{LifetimePolicy: FixedLifetime(time.Minute), maxRequests: 10, maxBytes: 100}
+	for _, tc := range []struct {
+		usage   ConnUsage
+		expired bool
+	}{
+		{ConnUsage{Age: time.Second, Lifetime: time.Minute, Requests: 9, Bytes: 99}, false},
+		{ConnUsage{Age: time.Minute, Lifetime: time.Minute}, true},
+		{ConnUsage{Age: time.Second, Lifetime: time.Minute, Requests: 10}, true},
+		{ConnUsage{Age: time.Second, Lifetime: time.Minute, Bytes: 100}, true},
+	} {
+		if got := p.Expired(tc.usage); got != tc.expired {
+			t.Fatalf("usage [%+v]: expected expired [%v], got [%v]", tc.usage, tc.expired, got)
+		}
+	}
+}
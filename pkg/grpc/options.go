@@ -17,11 +17,32 @@ type Dialer func(context.Context, string, ...grpc.DialOption) (*grpc.ClientConn,
 func (d Dialer) apply(o *options) { o.dialer = d }
 
 type options struct {
-	dialer         Dialer
-	dialOptions    []grpc.DialOption
-	poolSize       int
-	maxLifeTimeout time.Duration
-	stdDev         time.Duration
+	dialer                  Dialer
+	dialOptions             []grpc.DialOption
+	poolSize                int
+	maxLifeTimeout          time.Duration
+	stdDev                  time.Duration
+	refreshMode             RefreshMode
+	drainTimeout            time.Duration
+	warmupTimeout           time.Duration
+	selector                ConnSelector
+	autoscale               *AutoscaleConfig
+	minReadyConns           int
+	readyTimeout            time.Duration
+	refreshInterval         time.Duration
+	refreshJitter           float64
+	eventDrivenRefresh      bool
+	refreshRateLimit        time.Duration
+	clock                   Clock
+	lifetimePolicy          LifetimePolicy
+	maxRequests             int64
+	maxBytes                int64
+	distinctBackendAttempts int
+	targets                 []Target
+	tiers                   *TierConfig
+	retryPolicy             *RetryPolicy
+	hedgePolicy             *HedgePolicy
+	outlier                 *OutlierConfig
 }
 
 type ConnectionMaxLifeTime time.Duration
@@ -36,6 +57,101 @@ type PoolSize int
 
 func (s PoolSize) apply(o *options) { o.poolSize = int(s) }
 
+// RefreshMode decides how a connection in the pool is replaced when it gets refreshed
+type RefreshMode int
+
+const (
+	// RefreshSwap swaps in the new connection as soon as it is dialled
+	RefreshSwap RefreshMode = iota
+	// RefreshMakeBeforeBreak swaps in the new connection only once it has reached READY state,
+	// the old connection keeps serving RPCs till then
+	RefreshMakeBeforeBreak
+)
+
+func (m RefreshMode) apply(o *options) { o.refreshMode = m }
+
+// ConnectionDrainTimeout is the max time a replaced connection is kept open for its in-flight RPCs to finish,
+// so that a long-lived stream does not keep it open forever. Defaults to 30 seconds
+type ConnectionDrainTimeout time.Duration
+
+func (d ConnectionDrainTimeout) apply(o *options) {
+	o.drainTimeout = GetOrDefault[time.Duration](time.Duration(d), defaultConnDrainTimeout)
+}
+
+// ConnectionWarmupTimeout is the max time a new connection is given to reach READY state in RefreshMakeBeforeBreak mode
+type ConnectionWarmupTimeout time.Duration
+
+func (d ConnectionWarmupTimeout) apply(o *options) { o.warmupTimeout = time.Duration(d) }
+
+// MinReadyConnections makes the pool creation block till at least these many connections are in READY state.
+// A zero value creates the pool lazily, without waiting for any connection to be ready
+type MinReadyConnections int
+
+func (n MinReadyConnections) apply(o *options) { o.minReadyConns = int(n) }
+
+// ReadyTimeout is the max time the pool creation waits for MinReadyConnections to be ready
+type ReadyTimeout time.Duration
+
+func (d ReadyTimeout) apply(o *options) { o.readyTimeout = time.Duration(d) }
+
+// ConnectionMaxRequests is the no of RPCs after which a connection is marked unhealthy and refreshed.
+// It applies on top of the LifetimePolicy, a zero value means no limit
+type ConnectionMaxRequests int64
+
+func (n ConnectionMaxRequests) apply(o *options) { o.maxRequests = int64(n) }
+
+// ConnectionMaxBytes is the no of bytes sent and received after which a connection is marked unhealthy and refreshed.
+// It applies on top of the LifetimePolicy, a zero value means no limit
+type ConnectionMaxBytes int64
+
+func (n ConnectionMaxBytes) apply(o *options) { o.maxBytes = int64(n) }
+
+// DistinctBackends is the max no of times a connection is dialled, till it lands on a backend
+// which no other connection of the pool is on. 0 or 1 keeps the first connection dialled
+type DistinctBackends int
+
+func (n DistinctBackends) apply(o *options) { o.distinctBackendAttempts = int(n) }
+
+// Targets spreads the pool connections over multiple targets, in proportion to their weights.
+// A connection which keeps failing to reach its target fails over to the other targets
+type Targets []Target
+
+func (t Targets) apply(o *options) { o.targets = t }
+
+// RefreshInterval is the interval at which the unhealthy connections of the pool are refreshed in background
+type RefreshInterval time.Duration
+
+func (d RefreshInterval) apply(o *options) { o.refreshInterval = time.Duration(d) }
+
+// RefreshJitter is the fraction of the refresh interval by which every background refresh is randomly moved earlier or later.
+// It is capped at 0.9
+type RefreshJitter float64
+
+func (f RefreshJitter) apply(o *options) { o.refreshJitter = float64(f) }
+
+// EventDrivenRefresh watches the state of every connection, to refresh it as soon as it leaves READY state
+// instead of waiting for the next periodic refresh
+type EventDrivenRefresh bool
+
+func (b EventDrivenRefresh) apply(o *options) { o.eventDrivenRefresh = bool(b) }
+
+// RefreshRateLimit is the min time between two refreshes triggered by connection state changes
+type RefreshRateLimit time.Duration
+
+func (d RefreshRateLimit) apply(o *options) { o.refreshRateLimit = time.Duration(d) }
+
+// WithSelector overrides the Selector used to pick a connection from the pool for every RPC
+func WithSelector(s Selector) Option {
+	return WithConnSelector(AdaptSelector(s))
+}
+
+// WithConnSelector overrides the ConnSelector used to pick a connection from the pool for every RPC
+func WithConnSelector(s ConnSelector) Option {
+	return optionFunc(func(o *options) {
+		o.selector = s
+	})
+}
+
 // optionFunc is a helper function which appends all the grpc dialOptions to options in the list
 type optionFunc func(*options)
 
@@ -43,10 +159,19 @@ func (f optionFunc) apply(opts *options) { f(opts) }
 
 func wrapToOptions(opts []Option) *options {
 	opt := &options{
-		dialer:         grpc.DialContext,
-		poolSize:       defaultConnectionPoolSize,
-		maxLifeTimeout: defaultConnMaxTimeout,
-		stdDev:         defaultConnStdDeviation,
+		dialer:           grpc.DialContext,
+		poolSize:         defaultConnectionPoolSize,
+		maxLifeTimeout:   defaultConnMaxTimeout,
+		stdDev:           defaultConnStdDeviation,
+		refreshMode:      RefreshSwap,
+		drainTimeout:     defaultConnDrainTimeout,
+		warmupTimeout:    defaultConnWarmupTimeout,
+		selector:         AdaptSelector(&RoundRobinSelector{}),
+		readyTimeout:     defaultReadyTimeout,
+		refreshInterval:  defaultRefreshInterval,
+		refreshJitter:    defaultRefreshJitter,
+		refreshRateLimit: defaultRefreshRateLimit,
+		clock:            realClock{},
 	}
 
 	for _, o := range opts {
@@ -57,10 +182,48 @@ func wrapToOptions(opts []Option) *options {
 }
 
 func getPoolOptions(cfg *ClientConfig, opts []grpc.DialOption) []Option {
-	return []Option{
+	poolOpts := []Option{
 		WithDialOptions(getGRPCDialOptions(cfg, opts)...),
 		PoolSize(cfg.connectionPoolSize),
 		ConnectionMaxLifeTime(cfg.connectionMaxLifeTime),
 		ConnectionStandardDeviation(cfg.connectionLifeTimeDeviation),
+		cfg.refreshMode,
+		ConnectionDrainTimeout(cfg.connectionDrainTimeout),
+		ConnectionWarmupTimeout(cfg.connectionWarmupTimeout),
+		MinReadyConnections(cfg.minReadyConns),
+		ReadyTimeout(cfg.readyTimeout),
+		RefreshInterval(cfg.refreshInterval),
+		EventDrivenRefresh(cfg.eventDrivenRefresh),
+		RefreshRateLimit(cfg.refreshRateLimit),
+		ConnectionMaxRequests(cfg.connectionMaxRequests),
+		ConnectionMaxBytes(cfg.connectionMaxBytes),
+		DistinctBackends(cfg.distinctBackendAttempts),
+		Targets(cfg.targets),
+	}
+
+	if cfg.selector != nil {
+		poolOpts = append(poolOpts, WithConnSelector(cfg.selector))
+	}
+	if cfg.autoscale != nil {
+		poolOpts = append(poolOpts, WithAutoscale(*cfg.autoscale))
+	}
+	if cfg.targetTiers != nil {
+		poolOpts = append(poolOpts, WithTargetTiers(*cfg.targetTiers))
+	}
+	if cfg.retryPolicy != nil {
+		poolOpts = append(poolOpts, WithRetry(*cfg.retryPolicy))
+	}
+	if cfg.hedgePolicy != nil {
+		poolOpts = append(poolOpts, WithHedging(*cfg.hedgePolicy))
+	}
+	if cfg.outlierEjection != nil {
+		poolOpts = append(poolOpts, WithOutlierEjection(*cfg.outlierEjection))
+	}
+	if cfg.clock != nil {
+		poolOpts = append(poolOpts, WithClock(cfg.clock))
+	}
+	if cfg.lifetimePolicy != nil {
+		poolOpts = append(poolOpts, WithLifetimePolicy(cfg.lifetimePolicy))
 	}
+	return poolOpts
 }
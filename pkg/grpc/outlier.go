@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultOutlierConsecutiveFailures = 5
+	defaultOutlierMinRequests         = 20
+	defaultOutlierBaseEjection        = 30 * time.Second
+	defaultOutlierMaxEjection         = 5 * time.Minute
+	defaultOutlierMaxEjectedShare     = 0.5
+)
+
+// OutlierConfig configures the pool to eject the connections failing the RPCs from the selection for a backoff window,
+// e.g. the ones stuck on a sick server. Once the window is over, the connection is allowed back in half-open state,
+// where a single probe RPC is let through, the outcome of which decides if it stays in or gets ejected again for a longer window
+type OutlierConfig struct {
+	// FailureCodes are the status codes an RPC is counted as failed on. Defaults to UNAVAILABLE and INTERNAL
+	FailureCodes []codes.Code
+	// ConsecutiveFailures is the no of failed RPCs in a row which eject a connection
+	ConsecutiveFailures int
+	// ErrorRate, between 0 and 1, is the share of failed RPCs out of every MinRequests RPCs which ejects a connection.
+	// 0 turns off the ejection on error rate
+	ErrorRate   float64
+	MinRequests int
+	// BaseEjection is the window of the first ejection of a connection, it doubles for every ejection after it up to MaxEjection
+	BaseEjection time.Duration
+	MaxEjection  time.Duration
+	// MaxEjectedShare, between 0 and 1, is the max share of the pool connections which can be ejected at once
+	MaxEjectedShare float64
+}
+
+// WithOutlierEjection turns on the ejection of the failing connections of the pool
+func WithOutlierEjection(cfg OutlierConfig) Option {
+	return optionFunc(func(o *options) {
+		o.outlier = &cfg
+	})
+}
+
+func (cfg OutlierConfig) withDefaults() OutlierConfig {
+	if len(cfg.FailureCodes) == 0 {
+		cfg.FailureCodes = []codes.Code{codes.Unavailable, codes.Internal}
+	}
+	cfg.ConsecutiveFailures = GetOrDefault[int](cfg.ConsecutiveFailures, defaultOutlierConsecutiveFailures)
+	cfg.MinRequests = GetOrDefault[int](cfg.MinRequests, defaultOutlierMinRequests)
+	cfg.BaseEjection = GetOrDefault[time.Duration](cfg.BaseEjection, defaultOutlierBaseEjection)
+	cfg.MaxEjection = GetOrDefault[time.Duration](cfg.MaxEjection, defaultOutlierMaxEjection)
+	if cfg.MaxEjectedShare <= 0 || cfg.MaxEjectedShare > 1 {
+		cfg.MaxEjectedShare = defaultOutlierMaxEjectedShare
+	}
+	return cfg
+}
+
+func (cfg OutlierConfig) failed(err error) bool {
+	if err == nil {
+		return false
+	}
+	code := status.Code(err)
+	for _, c := range cfg.FailureCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// outlierState tracks the outcomes of the RPCs served by a connection, and its ejection
+type outlierState struct {
+	consecutive  int
+	requests     int
+	failures     int
+	ejections    int
+	ejectedUntil time.Time
+	halfOpen     bool
+	// probing is true while the probe RPC of a half-open connection is in-flight
+	probing bool
+	mu      sync.Mutex
+}
+
+// record records the outcome of an RPC started at start, and returns true if the connection should be ejected
+func (s *outlierState) record(failed bool, start time.Time, cfg OutlierConfig) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// outcome of an RPC which was in-flight when the connection got ejected, or was served from it while ejected
+	if start.Before(s.ejectedUntil) {
+		return false
+	}
+	if s.halfOpen {
+		s.halfOpen, s.probing = false, false
+		if !failed {
+			s.ejections = 0
+		}
+		return failed
+	}
+
+	s.requests++
+	if failed {
+		s.consecutive++
+		s.failures++
+	} else {
+		s.consecutive = 0
+	}
+	eject := s.consecutive >= cfg.ConsecutiveFailures
+	if s.requests >= cfg.MinRequests {
+		if cfg.ErrorRate > 0 && float64(s.failures)/float64(s.requests) >= cfg.ErrorRate {
+			eject = true
+		}
+		s.requests, s.failures = 0, 0
+	}
+	return eject
+}
+
+// eject ejects the connection for a window growing with the no of times it has been ejected in a row
+func (s *outlierState) eject(now time.Time, cfg OutlierConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d := float64(cfg.BaseEjection) * math.Pow(2, float64(s.ejections))
+	s.ejectedUntil = now.Add(time.Duration(math.Min(d, float64(cfg.MaxEjection))))
+	s.ejections++
+	s.consecutive, s.requests, s.failures = 0, 0, 0
+	s.halfOpen, s.probing = true, false
+}
+
+func (s *outlierState) ejected(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return now.Before(s.ejectedUntil)
+}
+
+// available returns false while the connection is ejected, or while the probe RPC of the half-open connection is in-flight
+func (s *outlierState) available(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !now.Before(s.ejectedUntil) && !(s.halfOpen && s.probing)
+}
+
+// admit returns true if an RPC can be served from the connection. Once the ejection window is over,
+// only the first RPC is admitted as the probe of the half-open connection till its outcome is recorded
+func (s *outlierState) admit(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.halfOpen || now.Before(s.ejectedUntil) {
+		return true
+	}
+	if s.probing {
+		return false
+	}
+	s.probing = true
+	return true
+}
+
+// recordOutcome ejects the connection once it fails too many RPCs, as long as the max share of the pool is not ejected already
+func (pool *clientConnPool) recordOutcome(cc *sharedConn, err error, start, now time.Time) {
+	if !cc.outlier.record(pool.outlier.failed(err), start, *pool.outlier) {
+		return
+	}
+
+	pool.ejectMu.Lock()
+	defer pool.ejectMu.Unlock()
+
+	conns := pool.snapshot()
+	ejected := 0
+	for _, c := range conns {
+		if conn, ok := c.current(); ok && conn.outlier.ejected(now) {
+			ejected++
+		}
+	}
+	if float64(ejected+1) > pool.outlier.MaxEjectedShare*float64(len(conns)) {
+		return
+	}
+	cc.outlier.eject(now, *pool.outlier)
+}
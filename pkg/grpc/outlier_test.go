@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestOutlierHalfOpenAdmitsSingleProbe(t *testing.T) {
+	cfg := OutlierConfig{ConsecutiveFailures: 2, BaseEjection: time.Minute}.withDefaults()
+	failure := status.Error(codes.Unavailable, "unavailable")
+	now := time.Unix(1000, 0)
+
+	var s outlierState
+	s.record(cfg.failed(failure), now, cfg)
+	if !s.record(cfg.failed(failure), now, cfg) {
+		t.Fatal("not ejected after the consecutive failures")
+	}
+	s.eject(now, cfg)
+	if s.available(now.Add(time.Second)) {
+		t.Fatal("available within the ejection window")
+	}
+
+	// ejection window is over
+	now = now.Add(2 * time.Minute)
+	if !s.available(now) || !s.admit(now) {
+		t.Fatal("probe not admitted once the ejection window is over")
+	}
+	if s.available(now) || s.admit(now) {
+		t.Fatal("second RPC admitted while the probe is in-flight")
+	}
+	// outcome of an RPC started before the connection got ejected does not settle the probe
+	if s.record(false, now.Add(-time.Hour), cfg) || s.available(now) {
+		t.Fatal("stale outcome settled the probe")
+	}
+
+	if s.record(false, now, cfg) {
+		t.Fatal("ejected after a successful probe")
+	}
+	for i := 0; i < 3; i++ {
+		if !s.available(now) || !s.admit(now) {
+			t.Fatal("RPC not admitted after a successful probe")
+		}
+	}
+}
+
+func TestOutlierFailedProbeEjectsAgain(t *testing.T) {
+	cfg := OutlierConfig{ConsecutiveFailures: 1, BaseEjection: time.Minute}.withDefaults()
+	now := time.Unix(1000, 0)
+
+	var s outlierState
+	s.eject(now, cfg)
+	now = now.Add(time.Minute)
+	if !s.admit(now) {
+		t.Fatal("probe not admitted once the ejection window is over")
+	}
+	if !s.record(true, now, cfg) {
+		t.Fatal("not ejected after a failed probe")
+	}
+	s.eject(now, cfg)
+	// second ejection is twice as long
+	if s.available(now.Add(90 * time.Second)) {
+		t.Fatal("available within the second ejection window")
+	}
+	if !s.available(now.Add(2 * time.Minute)) {
+		t.Fatal("not available once the second ejection window is over")
+	}
+}
+
+func TestOutlierEjectsFailingConn(t *testing.T) {
+	bad, good := startFailingServer(t), startServer(t)
+	cfg := ClientConfigBuilder().
+		WithTargets(bad, good).
+		WithPoolSize(2).
+		WithMinReadyConns(2).
+		WithOutlierEjection(OutlierConfig{ConsecutiveFailures: 3, BaseEjection: 200 * time.Millisecond}).
+		Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+
+	hc := hpb.NewHealthClient(c)
+	failures := func() int {
+		n := 0
+		for i := 0; i < 20; i++ {
+			if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+				n++
+			}
+		}
+		return n
+	}
+	if n := failures(); n != 3 {
+		t.Fatalf("[%d] failed RPCs before the ejection, expected 3", n)
+	}
+	time.Sleep(250 * time.Millisecond)
+	// the probe fails and ejects the connection again
+	if n := failures(); n != 1 {
+		t.Fatalf("[%d] failed RPCs after the ejection window, expected 1", n)
+	}
+}
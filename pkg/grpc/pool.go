@@ -8,109 +8,356 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/go-co-op/gocron"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/protobuf/proto"
 )
 
 type clientConnPool struct {
-	target      string
-	opts        *options
-	currIndex   Selector
-	conns       []*clientConn
-	connsMu     sync.Mutex
+	targets  atomic.Value // *targetPicker
+	opts     *options
+	selector ConnSelector
+	conns    []*clientConn
+	size     int
+	connsMu  sync.Mutex
+	// changed is closed and replaced every time the connections of the pool change, guarded by connsMu
+	changed     chan struct{}
 	refreshMu   sync.Mutex
+	resizeMu    sync.Mutex
 	lastDialErr atomic.Value
 	_closed     uint32
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+	lifeMu      sync.RWMutex
+	rnd         *rand.Rand
+	rndMu       sync.Mutex
+	lifetime    LifetimePolicy
+	healCh      chan struct{}
+	refreshCh   chan struct{}
+	backends    map[*sharedConn]struct{}
+	backendsMu  sync.Mutex
+	retrier     *retrier
+	hedger      *hedger
+	outlier     *OutlierConfig
+	ejectMu     sync.Mutex
 }
 
 func (pool *clientConnPool) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
-	c, err := pool.get()
-	if err != nil {
+	invoke := func(ctx context.Context) (*sharedConn, error) {
+		if out, ok := reply.(proto.Message); ok && pool.hedger != nil && pool.hedger.hedged(method) {
+			return pool.invokeHedged(ctx, method, args, out, opts)
+		}
+
+		cc, err := pool.acquire(ctx, method)
+		if err != nil {
+			return nil, err
+		}
+
+		return cc, pool.invokeConn(ctx, cc, method, args, reply, opts)
+	}
+
+	if pool.retrier == nil {
+		_, err := invoke(ctx)
 		return err
 	}
-	return c.conn.Invoke(ctx, method, args, reply, opts...)
+	return pool.invokeWithRetry(ctx, invoke)
 }
 
 func (pool *clientConnPool) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
-	// TODO: fill the implementation of new stream over grpc connection pool
-	panic("not implemented yet")
+	cc, err := pool.acquire(ctx, method)
+	if err != nil {
+		return nil, err
+	}
+
+	// the connection is held till the stream finishes, so that a refresh of the slot does not close it underneath.
+	// The lifetime of a stream says nothing of the latency of the connection, so only its outcome is recorded
+	once := sync.Once{}
+	start := pool.opts.clock.Now()
+	release := func(err error) { once.Do(func() { pool.done(cc, start, err) }) }
+
+	callOpts := make([]grpc.CallOption, 0, len(opts)+1)
+	callOpts = append(callOpts, opts...)
+	callOpts = append(callOpts, grpc.OnFinish(release))
+
+	stream, err := cc.NewStream(ctx, desc, method, callOpts...)
+	if err != nil {
+		release(err)
+		return nil, err
+	}
+	return stream, nil
 }
 
 func newConnPool(target string, opts ...Option) (*clientConnPool, error) {
 	p := &clientConnPool{
-		target:    target,
-		opts:      wrapToOptions(opts),
-		currIndex: &RoundRobinSelector{mu: sync.Mutex{}},
+		opts: wrapToOptions(opts),
+	}
+	targets := p.opts.targets
+	if len(targets) == 0 {
+		targets = []Target{{Addr: target}}
+	}
+	var tiers TierConfig
+	if p.opts.tiers != nil {
+		tiers = p.opts.tiers.withDefaults()
+		if len(tiers.Tiers) > 0 {
+			targets = tiers.Tiers[0]
+		}
+	}
+	p.targets.Store(newTargetPicker(targets))
+	p.selector = p.opts.selector
+	p.rnd = rand.New(rand.NewSource(p.opts.clock.Now().UnixNano()))
+	p.lifetime = p.opts.lifetimePolicy
+	if p.lifetime == nil {
+		p.lifetime = UniformJitterLifetime(p.opts.maxLifeTimeout, p.opts.stdDev)
+	}
+	if p.opts.maxRequests > 0 || p.opts.maxBytes > 0 {
+		p.lifetime = budgetLifetime{LifetimePolicy: p.lifetime, maxRequests: p.opts.maxRequests, maxBytes: p.opts.maxBytes}
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	p.changed = make(chan struct{})
+	p.healCh = make(chan struct{}, 1)
+	p.refreshCh = make(chan struct{}, 1)
+	p.backends = make(map[*sharedConn]struct{})
+	if p.opts.retryPolicy != nil {
+		p.retrier = newRetrier(*p.opts.retryPolicy)
+	}
+	if p.opts.hedgePolicy != nil {
+		p.hedger = newHedger(*p.opts.hedgePolicy)
+	}
+	if p.opts.outlier != nil {
+		outlier := p.opts.outlier.withDefaults()
+		p.outlier = &outlier
+	}
+
+	var autoscale AutoscaleConfig
+	if p.opts.autoscale != nil {
+		autoscale = p.opts.autoscale.withDefaults(p.opts.poolSize)
+		p.opts.poolSize = clamp(p.opts.poolSize, autoscale.MinSize, autoscale.MaxSize)
 	}
 
 	// initialize the client connection pool
-	p.init()
+	dialErrs := p.init()
 
 	// refresh connection in background
 	err := p.asyncRefresh()
 	if err != nil {
-		return nil, fmt.Errorf("[%s]. errors is: [%s]", asyncRefreshInitErr, err)
+		_ = p.Close(context.Background())
+		return nil, fmt.Errorf("[%w], error is: [%w]", ErrAsyncRefreshInit, err)
+	}
+
+	// refresh the connections on events like a connection leaving READY state or using up its budget
+	p.goAsync(p.refreshOnEvents)
+
+	// re-dial the connections which failed to dial in background
+	p.goAsync(p.heal)
+	if p.missing() > 0 {
+		p.triggerHeal()
+	}
+
+	// scale the pool with the load in background
+	if p.opts.autoscale != nil {
+		p.goAsync(func() { p.autoscale(autoscale) })
+	}
+
+	if len(tiers.Tiers) > 1 {
+		p.goAsync(func() { p.watchTiers(tiers) })
+	}
+
+	// fail fast if the min no of connections do not get ready in time
+	if p.opts.minReadyConns > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), p.opts.readyTimeout)
+		err = p.awaitReady(ctx, p.opts.minReadyConns, dialErrs)
+		cancel()
+		if err != nil {
+			_ = p.Close(context.Background())
+			return nil, err
+		}
 	}
 
 	return p, nil
 }
 
-func (pool *clientConnPool) init() {
+// init dials all the connections of the pool, and returns the errors of the ones which failed to dial
+func (pool *clientConnPool) init() []error {
+	pool.size = pool.opts.poolSize
+
+	dialErrs := make([]error, 0)
 	wg := &sync.WaitGroup{}
 	// initialize all the grpc connections in async
 	for i := 0; i < pool.opts.poolSize; i++ {
 		wg.Add(1)
 		go func(wg *sync.WaitGroup) {
-			if c, e := pool.dialConn(); e == nil {
-				pool.connsMu.Lock()
+			c, e := pool.dialConn()
+			pool.connsMu.Lock()
+			if e != nil {
+				dialErrs = append(dialErrs, e)
+			} else {
 				pool.conns = append(pool.conns, c)
-				pool.connsMu.Unlock()
+				pool.notifyConnsChanged()
 			}
+			pool.connsMu.Unlock()
 			wg.Done()
 		}(wg)
 	}
 
 	// wait till all the connections are initialized
 	wg.Wait()
+	return dialErrs
+}
+
+// goAsync runs fn in a background goroutine tied to the lifecycle of the pool.
+// fn should return once pool.ctx is done. It returns false without running fn if the pool is closed
+func (pool *clientConnPool) goAsync(fn func()) bool {
+	if !pool.enter() {
+		return false
+	}
+	go func() {
+		defer pool.wg.Done()
+		fn()
+	}()
+	return true
+}
+
+// enter registers a background worker with the pool, which Close waits for till it calls pool.wg.Done.
+// It returns false if the pool is closed
+func (pool *clientConnPool) enter() bool {
+	pool.lifeMu.RLock()
+	defer pool.lifeMu.RUnlock()
+
+	if pool.ctx.Err() != nil {
+		return false
+	}
+	pool.wg.Add(1)
+	return true
+}
+
+// retire closes a connection taken out of the pool once all the RPCs in-flight over it are finished,
+// the drain timeout is reached or the pool is closed
+func (pool *clientConnPool) retire(conn *sharedConn) {
+	conn.retire()
+
+	started := pool.goAsync(func() {
+		t := pool.opts.clock.NewTimer(pool.opts.drainTimeout)
+		defer t.Stop()
+
+		select {
+		case <-conn.drained:
+		case <-t.C():
+		case <-pool.ctx.Done():
+		}
+		_ = conn.Close()
+	})
+	if !started {
+		_ = conn.Close()
+	}
+}
+
+// wait blocks for d as per the clock of the pool, it returns false if the pool got closed in between
+func (pool *clientConnPool) wait(d time.Duration) bool {
+	t := pool.opts.clock.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-pool.ctx.Done():
+		return false
+	case <-t.C():
+		return true
+	}
+}
+
+// snapshot returns the connections currently in the pool. The returned slice must not be modified,
+// the pool never changes the connections already present in a slice handed out, only replaces pool.conns
+func (pool *clientConnPool) snapshot() []*clientConn {
+	pool.connsMu.Lock()
+	defer pool.connsMu.Unlock()
+
+	return pool.conns
+}
+
+// connsChanged returns a channel which is closed once the connections of the pool change
+func (pool *clientConnPool) connsChanged() <-chan struct{} {
+	pool.connsMu.Lock()
+	defer pool.connsMu.Unlock()
+
+	return pool.changed
+}
+
+// notifyConnsChanged wakes up everyone waiting on connsChanged, it should be called with connsMu held
+func (pool *clientConnPool) notifyConnsChanged() {
+	close(pool.changed)
+	pool.changed = make(chan struct{})
 }
 
 func (pool *clientConnPool) storeLastDialErr(err error) {
 	pool.lastDialErr.Store(
 		ErrMap{
 			Err:        err,
-			OccurredAt: time.Now(),
+			OccurredAt: pool.opts.clock.Now(),
 		})
 }
 
-func (pool *clientConnPool) dialConn() (*clientConn, error) {
-	conn, err := pool.opts.dialer(context.Background(), pool.target, pool.opts.dialOptions...)
+// dial dials a new grpc connection to the target, along with the stats handler recording its usage
+func (pool *clientConnPool) dial(target string) (*sharedConn, error) {
+	stats := &connStats{
+		expired:   pool.usageExpired,
+		onExpired: pool.triggerRefresh,
+	}
+	dialOpts := make([]grpc.DialOption, 0, len(pool.opts.dialOptions)+1)
+	dialOpts = append(dialOpts, pool.opts.dialOptions...)
+	dialOpts = append(dialOpts, grpc.WithStatsHandler(stats))
+
+	conn, err := pool.opts.dialer(pool.ctx, target, dialOpts...)
 	if err != nil {
 		pool.storeLastDialErr(err)
-		return nil, grpcDialErr
+		return nil, &DialError{Target: target, Err: err}
 	}
+	return newSharedConn(pool.ctx, conn, stats), nil
+}
 
-	c := wrapToClientConn(conn)
+func (pool *clientConnPool) dialConn() (*clientConn, error) {
+	target := pool.picker().next("")
+	conn, err := pool.dialDistinct(target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c := wrapToClientConn(conn, target, pool.opts.clock.Now())
 	c.setDeadline(pool.connLifeTimeout())
 
+	// refresh the connection as soon as it leaves READY state
+	if pool.opts.eventDrivenRefresh {
+		pool.goAsync(func() { pool.watch(c) })
+	}
+
 	return c, nil
 }
 
 func (pool *clientConnPool) connLifeTimeout() time.Duration {
-	newRandomNo := rand.New(rand.NewSource(time.Now().UnixNano())).Float64() + 0.5
+	pool.rndMu.Lock()
+	defer pool.rndMu.Unlock()
 
-	return time.Duration(float64(pool.opts.maxLifeTimeout) + (newRandomNo * float64(pool.opts.stdDev)))
+	return pool.lifetime.Lifetime(pool.rnd)
+}
+
+// usageExpired reports whether a connection has reached the end of its life by the RPCs and bytes served over it alone
+func (pool *clientConnPool) usageExpired(requests, bytes int64) bool {
+	return pool.lifetime.Expired(ConnUsage{Lifetime: maxDuration, Requests: requests, Bytes: bytes})
 }
 
 func (pool *clientConnPool) shouldRefresh(c *clientConn) bool {
-	now := time.Now()
+	now := pool.opts.clock.Now()
 	c.cMu.Lock()
 	defer func() {
 		c.cMu.Unlock()
 	}()
 
-	// check if deadline has been exceeded
-	if now.Sub(c.createdAt) >= c.deadline() {
+	// check if connection has reached the end of its life or used up its budget
+	if pool.lifetime.Expired(c.usage(now)) {
+		return true
+	}
+
+	// check if the pool has moved to another tier of targets
+	if !pool.picker().has(c.target) {
 		return true
 	}
 
@@ -122,15 +369,14 @@ func (pool *clientConnPool) shouldRefresh(c *clientConn) bool {
 }
 
 func (pool *clientConnPool) asyncRefresh() error {
-	s := gocron.NewScheduler(time.UTC)
-	task := func(job gocron.Job) {
-		pool.refreshInBackground()
+	if pool.opts.refreshInterval <= 0 {
+		return fmt.Errorf("invalid refresh interval [%s]", pool.opts.refreshInterval)
 	}
-	_, err := s.Every(30).Seconds().DoWithJobDetails(task)
-	if err != nil {
-		return fmt.Errorf("[%s], error is: [%s]", cronErr, err)
-	}
-	s.StartAsync()
+
+	s := newScheduler(pool.opts.refreshInterval, pool.opts.refreshJitter, pool.opts.clock)
+	pool.goAsync(func() {
+		s.run(pool.ctx, pool.refreshInBackground)
+	})
 	return nil
 }
 
@@ -140,30 +386,66 @@ func (pool *clientConnPool) refreshConnection(c *clientConn) error {
 		return nil
 	}
 
-	newConn, err := pool.opts.dialer(context.Background(), pool.target, pool.opts.dialOptions...)
+	c.cMu.Lock()
+	oldConn := c.conn
+	switch targets := pool.picker(); {
+	case !targets.has(c.target):
+		// the pool has moved to another tier of targets
+		c.target, c.failures = targets.next(""), 0
+	case oldConn.GetState() == connectivity.TransientFailure:
+		// the slot fails over to another target, once its target keeps failing
+		pool.recordFailure(c)
+	default:
+		c.failures = 0
+	}
+	target := c.target
+	c.cMu.Unlock()
+
+	newConn, err := pool.dialDistinct(target, oldConn)
 	if err != nil {
-		pool.storeLastDialErr(err)
-		return fmt.Errorf("[%s], error is: [%s]", grpcDialErr, err)
+		pool.recordSlotFailure(c)
+		return err
 	}
-	pool.connsMu.Lock()
 
-	// close in background
-	go func(cc *grpc.ClientConn) {
-		_ = cc.Close()
-	}(c.conn)
+	// keep serving RPCs from the old connection till the new one is ready
+	if pool.opts.refreshMode == RefreshMakeBeforeBreak {
+		ctx, cancel := context.WithTimeout(pool.ctx, pool.opts.warmupTimeout)
+		err = waitForReady(ctx, newConn.ClientConn)
+		cancel()
+		if err != nil {
+			_ = newConn.Close()
+			pool.recordSlotFailure(c)
+			return fmt.Errorf("[%w], error is: [%w]", ErrConnWarmup, err)
+		}
+	}
+
+	pool.connsMu.Lock()
 
 	c.cMu.Lock()
+	// slot got removed or the pool got closed while the new connection was being dialled
+	if c.removed || pool.closed() {
+		c.cMu.Unlock()
+		pool.connsMu.Unlock()
+		_ = newConn.Close()
+		return nil
+	}
+	oldConn = c.conn
 	c.conn = newConn
-	c.createdAt = time.Now()
+	c.createdAt = pool.opts.clock.Now()
 	c.setDeadline(pool.connLifeTimeout())
 	c.cMu.Unlock()
+	pool.notifyConnsChanged()
 
 	pool.connsMu.Unlock()
+
+	// old connection gets closed once all the RPCs in-flight over it are finished or drain timeout is reached
+	pool.retire(oldConn)
 	return nil
 }
 
 func (pool *clientConnPool) refreshInBackground() {
-	if pool.conns == nil {
+	conns := pool.snapshot()
+	if conns == nil {
 		return
 	}
 
@@ -172,7 +454,7 @@ func (pool *clientConnPool) refreshInBackground() {
 
 	// get all unhealthy connections
 	unhealthyConns := make([]*clientConn, 0)
-	for _, connect := range pool.conns {
+	for _, connect := range conns {
 		c := connect
 		if pool.shouldRefresh(c) {
 			unhealthyConns = append(unhealthyConns, c)
@@ -194,32 +476,121 @@ func (pool *clientConnPool) refreshInBackground() {
 	pool.refreshMu.Unlock()
 }
 
-func (pool *clientConnPool) get() (*clientConn, error) {
-	idx := pool.currIndex.Select(len(pool.conns))
-	conn := pool.conns[idx]
+// invokeConn serves the unary RPC from the acquired connection and releases it. Only the latency of a successful RPC is recorded,
+// as a failed or cancelled RPC, e.g. a hedge which lost, says nothing of how fast the connection serves the RPCs
+func (pool *clientConnPool) invokeConn(ctx context.Context, cc *sharedConn, method string, args, reply any, opts []grpc.CallOption) error {
+	start := pool.opts.clock.Now()
+	err := cc.Invoke(ctx, method, args, reply, opts...)
+	if err == nil {
+		now := pool.opts.clock.Now()
+		cc.latency.record(now.Sub(start), now)
+	}
+	pool.done(cc, start, err)
+	return err
+}
+
+// done records the outcome of an RPC served by the connection since start, and releases the connection
+func (pool *clientConnPool) done(cc *sharedConn, start time.Time, err error) {
+	if pool.outlier != nil {
+		pool.recordOutcome(cc, err, start, pool.opts.clock.Now())
+	}
+	cc.release()
+}
+
+// acquire picks a connection from the pool to serve the RPC, holding a reference on it till it is released
+func (pool *clientConnPool) acquire(ctx context.Context, method string) (*sharedConn, error) {
+	for {
+		c, err := pool.get(ctx, method)
+		if err != nil {
+			return nil, err
+		}
+		cc, ok := c.acquire()
+		if !ok {
+			// slot got removed from the pool in between, select again
+			continue
+		}
+		// another RPC is already probing the half-open connection, select again from the other connections
+		if pool.outlier != nil && !cc.outlier.admit(pool.opts.clock.Now()) {
+			cc.release()
+			excluded := excludedConnsFrom(ctx)
+			if excluded.has(c) {
+				return nil, fmt.Errorf("[%w], connection is being probed", ErrNoHealthyConn)
+			}
+			ctx = withExcludedConns(ctx, append(excludedConns{cc}, excluded...))
+			continue
+		}
+		return cc, nil
+	}
+}
+
+func (pool *clientConnPool) get(ctx context.Context, method string) (*clientConn, error) {
+	if pool.closed() {
+		return nil, ErrPoolClosed
+	}
+	conns := pool.snapshot()
+	if len(conns) == 0 {
+		return nil, ErrNoConnAvailable
+	}
+	excluded := excludedConnsFrom(ctx)
+	idx := pool.selectIndex(ctx, method, conns, excluded)
+	if idx < 0 || idx >= len(conns) {
+		return nil, fmt.Errorf("[%w], selected index [%d] out of [%d] connections", ErrNoHealthyConn, idx, len(conns))
+	}
+	conn := conns[idx]
 
-	// if current connection is unhealthy, serve the RPC from next available healthy connection
-	if !pool.isHealthyConn(conn) {
-		if healthyConn, err := pool.getNextHealthyConn(idx, len(pool.conns)); err == nil {
+	// if current connection is unhealthy or excluded, serve the RPC from next available healthy connection
+	if healthy := pool.isHealthyConn(conn); !healthy || excluded.has(conn) {
+		if healthyConn, err := pool.getNextHealthyConn(conns, idx, excluded); err == nil {
 			return healthyConn, nil
 		}
+		// all the other connections are either unhealthy or excluded as well, so serve the RPC from the excluded one
+		if healthy {
+			return conn, nil
+		}
 		// since no healthy connection found. Dial in sync to create a healthy connection to serve this RPC
 		err := pool.refreshConnection(conn)
 		if err != nil {
-			return nil, fmt.Errorf("[%s], error is: [%s]", connRefreshErr, err)
+			return nil, fmt.Errorf("[%w], [%w], error is: [%w]", ErrNoHealthyConn, ErrConnRefresh, err)
 		}
 	}
 	// if current connection is healthy, return this connection
 	return conn, nil
 }
 
+// selectIndex picks the index of the connection to serve the RPC from, as decided by the ConnSelector of the pool
+func (pool *clientConnPool) selectIndex(ctx context.Context, method string, conns []*clientConn, excluded excludedConns) int {
+	// a plain Selector only needs the no of connections, skip taking the snapshot of every connection
+	if a, ok := pool.selector.(selectorAdapter); ok {
+		if _, loadAware := a.s.(LoadAwareSelector); !loadAware {
+			return a.s.Select(len(conns))
+		}
+	}
+
+	now := pool.opts.clock.Now()
+	infos := make([]ConnInfo, len(conns))
+	for i, c := range conns {
+		infos[i] = c.info()
+		infos[i].Age = now.Sub(infos[i].CreatedAt)
+		if conn, ok := c.current(); ok {
+			infos[i].Latency = conn.latency.get(now)
+		}
+		infos[i].Healthy = pool.isHealthyConn(c) && !excluded.has(c)
+	}
+	return pool.selector.SelectConn(SelectInfo{Ctx: ctx, Method: method, Conns: infos})
+}
+
 func (pool *clientConnPool) isHealthyConn(c *clientConn) bool {
-	now := time.Now()
+	now := pool.opts.clock.Now()
 	c.cMu.Lock()
 	defer func() {
 		c.cMu.Unlock()
 	}()
-	if now.Sub(c.createdAt) >= c.deadline() {
+	if pool.lifetime.Expired(c.usage(now)) {
+		return false
+	}
+
+	// connection is ejected for failing too many RPCs, or its half-open probe is in-flight
+	if pool.outlier != nil && !c.conn.outlier.available(now) {
 		return false
 	}
 
@@ -230,26 +601,32 @@ func (pool *clientConnPool) isHealthyConn(c *clientConn) bool {
 	return false
 }
 
-func (pool *clientConnPool) getNextHealthyConn(curr, max int) (*clientConn, error) {
+func (pool *clientConnPool) getNextHealthyConn(conns []*clientConn, curr int, excluded excludedConns) (*clientConn, error) {
 	ptr := curr
 	for {
-		ptr = (ptr + 1) % max
+		ptr = (ptr + 1) % len(conns)
 		if ptr == curr {
 			break
 		}
-		if pool.isHealthyConn(pool.conns[ptr]) {
-			return pool.conns[ptr], nil
+		if pool.isHealthyConn(conns[ptr]) && !excluded.has(conns[ptr]) {
+			return conns[ptr], nil
 		}
 	}
-	return nil, noHealthyConnAvailableErr
+	return nil, ErrNoHealthyConn
 }
 
-// Close will close all the active connections in the clientConnPool
-func (pool *clientConnPool) Close() error {
+// Close will close all the active connections in the clientConnPool and stop all its background goroutines.
+// It returns once all the background goroutines have exited, or with an error if ctx is done before that
+func (pool *clientConnPool) Close(ctx context.Context) error {
 	if !atomic.CompareAndSwapUint32(&pool._closed, 0, 1) {
-		return connPoolCloseErr
+		return ErrPoolClosed
 	}
 
+	// stop accepting new background goroutines and signal the running ones to exit
+	pool.lifeMu.Lock()
+	pool.cancel()
+	pool.lifeMu.Unlock()
+
 	pool.connsMu.Lock()
 
 	wg := &sync.WaitGroup{}
@@ -258,7 +635,7 @@ func (pool *clientConnPool) Close() error {
 	for _, c := range pool.conns {
 		wg.Add(1)
 		go func(c *clientConn) {
-			_ = c.conn.Close()
+			_ = c.close()
 			wg.Done()
 		}(c)
 	}
@@ -266,10 +643,22 @@ func (pool *clientConnPool) Close() error {
 	wg.Wait()
 
 	pool.conns = nil
+	pool.notifyConnsChanged()
 
 	pool.connsMu.Unlock()
 
-	return nil
+	done := make(chan struct{})
+	go func() {
+		pool.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("[%w], error is: [%w]", ErrPoolClose, ctx.Err())
+	}
 }
 
 func (pool *clientConnPool) closed() bool {
@@ -277,6 +666,9 @@ func (pool *clientConnPool) closed() bool {
 }
 
 func (pool *clientConnPool) DialErr() error {
-	em := pool.lastDialErr.Load().(ErrMap)
+	em, ok := pool.lastDialErr.Load().(ErrMap)
+	if !ok {
+		return nil
+	}
 	return em.Err
 }
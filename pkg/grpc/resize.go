@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Resize grows or shrinks the pool to n connections. New connections are dialled before being added to the pool,
+// and the surplus connections are removed from the pool first and closed once their in-flight RPCs are drained.
+// If some of the new connections fail to dial, an error is returned and they keep being re-dialled in background.
+// It is safe to call Resize while RPCs are being served and connections are being refreshed in background
+func (pool *clientConnPool) Resize(n int) error {
+	if n <= 0 {
+		return ErrInvalidPoolSize
+	}
+	if pool.closed() {
+		return ErrPoolClosed
+	}
+
+	pool.resizeMu.Lock()
+	defer pool.resizeMu.Unlock()
+
+	pool.connsMu.Lock()
+	pool.size = n
+	curr := len(pool.conns)
+	pool.connsMu.Unlock()
+
+	switch {
+	case n > curr:
+		if err := pool.grow(n - curr); err != nil {
+			// connections which failed to dial are re-dialled in background
+			pool.triggerHeal()
+			return err
+		}
+	case n < curr:
+		pool.shrink(n)
+	}
+	return nil
+}
+
+// grow dials count new connections and appends the successfully dialled ones to the pool
+func (pool *clientConnPool) grow(count int) error {
+	newConns := make([]*clientConn, 0, count)
+	dialErrs := make([]error, 0)
+	mu := sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := pool.dialConn()
+			mu.Lock()
+			if err != nil {
+				dialErrs = append(dialErrs, err)
+			} else {
+				newConns = append(newConns, c)
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	pool.connsMu.Lock()
+	// pool got closed while the connections were being dialled
+	if pool.closed() {
+		pool.connsMu.Unlock()
+		for _, c := range newConns {
+			_ = c.close()
+		}
+		return ErrPoolClosed
+	}
+	conns := make([]*clientConn, 0, len(pool.conns)+len(newConns))
+	conns = append(conns, pool.conns...)
+	pool.conns = append(conns, newConns...)
+	pool.notifyConnsChanged()
+	pool.connsMu.Unlock()
+
+	if len(dialErrs) > 0 {
+		return fmt.Errorf("dialled [%d] out of [%d] connections, error is: [%w]", len(newConns), count, errors.Join(dialErrs...))
+	}
+	return nil
+}
+
+// shrink removes all the connections beyond the first n from the pool
+func (pool *clientConnPool) shrink(n int) {
+	pool.connsMu.Lock()
+	removed := pool.conns[n:]
+	pool.conns = append([]*clientConn(nil), pool.conns[:n]...)
+	pool.notifyConnsChanged()
+	pool.connsMu.Unlock()
+
+	for _, c := range removed {
+		pool.retire(c.remove())
+	}
+}
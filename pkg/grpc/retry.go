@@ -0,0 +1,198 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 50 * time.Millisecond
+	defaultRetryMaxBackoff     = time.Second
+	defaultRetryMultiplier     = 2
+	defaultRetryJitter         = 0.2
+	defaultRetryBudgetTokens   = 10
+	defaultRetryBudgetRatio    = 0.1
+)
+
+// RetryPolicy configures the pool to retry the failed unary RPCs on a different connection of the pool.
+// Only idempotent RPCs should be served from a pool with a retry policy
+type RetryPolicy struct {
+	// MaxAttempts is the max no of attempts of an RPC, including the first one
+	MaxAttempts int
+	// RetryableCodes are the status codes an RPC is retried on. Defaults to UNAVAILABLE
+	RetryableCodes []codes.Code
+	// InitialBackoff is the backoff before the first retry, it grows by Multiplier up to MaxBackoff for every retry after it
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter is the random share, between 0 and 1, by which every backoff is increased or decreased
+	Jitter float64
+	// BudgetTokens is the max no of retries the pool can make in a burst. Every retry takes a token,
+	// and every successful RPC gives back BudgetRatio tokens, which caps the retries at a share of the successful RPCs.
+	// With the defaults of 10 tokens and 0.1 token per success, a pool with half of its connections failing
+	// stops retrying after about 10 retries, and from then on retries about 1 RPC for every 10 successful ones
+	BudgetTokens float64
+	BudgetRatio  float64
+}
+
+// WithRetry turns on the retry of the failed unary RPCs of the pool
+func WithRetry(policy RetryPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.retryPolicy = &policy
+	})
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	p.MaxAttempts = GetOrDefault[int](p.MaxAttempts, defaultRetryMaxAttempts)
+	if len(p.RetryableCodes) == 0 {
+		p.RetryableCodes = []codes.Code{codes.Unavailable}
+	}
+	p.InitialBackoff = GetOrDefault[time.Duration](p.InitialBackoff, defaultRetryInitialBackoff)
+	p.MaxBackoff = GetOrDefault[time.Duration](p.MaxBackoff, defaultRetryMaxBackoff)
+	if p.Multiplier < 1 {
+		p.Multiplier = defaultRetryMultiplier
+	}
+	if p.Jitter <= 0 || p.Jitter > 1 {
+		p.Jitter = defaultRetryJitter
+	}
+	if p.BudgetTokens <= 0 {
+		p.BudgetTokens = defaultRetryBudgetTokens
+	}
+	if p.BudgetRatio <= 0 {
+		p.BudgetRatio = defaultRetryBudgetRatio
+	}
+	return p
+}
+
+// retrier decides on the retries of the pool, and keeps the pool wide retry budget
+type retrier struct {
+	policy RetryPolicy
+	tokens float64
+	mu     sync.Mutex
+}
+
+func newRetrier(policy RetryPolicy) *retrier {
+	policy = policy.withDefaults()
+	return &retrier{policy: policy, tokens: policy.BudgetTokens}
+}
+
+// retry returns true if the attempt of an RPC which failed with err should be retried, taking a token from the budget
+func (r *retrier) retry(attempt int, err error) bool {
+	if attempt >= r.policy.MaxAttempts || !r.retryable(err) {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// succeeded gives back a share of a token to the budget for a successful RPC
+func (r *retrier) succeeded() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens = math.Min(r.tokens+r.policy.BudgetRatio, r.policy.BudgetTokens)
+}
+
+func (r *retrier) retryable(err error) bool {
+	code := status.Code(err)
+	for _, c := range r.policy.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the backoff before the retry following the attempt, with jitter
+func (r *retrier) backoff(attempt int, jitter float64) time.Duration {
+	d := float64(r.policy.InitialBackoff) * math.Pow(r.policy.Multiplier, float64(attempt-1))
+	d = math.Min(d, float64(r.policy.MaxBackoff))
+	return time.Duration(d * (1 + r.policy.Jitter*(2*jitter-1)))
+}
+
+// invokeWithRetry serves the RPC through invoke, retrying it as per the retry policy of the pool.
+// Every retry goes to a connection other than the ones the previous attempts failed on, as long as the pool has one
+func (pool *clientConnPool) invokeWithRetry(ctx context.Context, invoke func(ctx context.Context) (*sharedConn, error)) error {
+	failed := make(excludedConns, 0)
+	for attempt := 1; ; attempt++ {
+		cc, err := invoke(withExcludedConns(ctx, failed))
+		if err == nil {
+			pool.retrier.succeeded()
+			return nil
+		}
+		if cc == nil || ctx.Err() != nil || !pool.retrier.retry(attempt, err) {
+			return err
+		}
+		failed = append(failed, cc)
+
+		pool.rndMu.Lock()
+		backoff := pool.retrier.backoff(attempt, pool.rnd.Float64())
+		pool.rndMu.Unlock()
+		if !pool.sleep(ctx, backoff) {
+			return err
+		}
+	}
+}
+
+// sleep waits for d, it returns false if ctx is done or the pool is closed in the meantime
+func (pool *clientConnPool) sleep(ctx context.Context, d time.Duration) bool {
+	t := pool.opts.clock.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-pool.ctx.Done():
+		return false
+	case <-t.C():
+		return true
+	}
+}
+
+type excludedConnsKey struct{}
+
+// excludedConns are the connections an RPC should not be served from, e.g. the ones its previous attempts failed on
+type excludedConns []*sharedConn
+
+func withExcludedConns(ctx context.Context, conns excludedConns) context.Context {
+	if len(conns) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, excludedConnsKey{}, conns)
+}
+
+func excludedConnsFrom(ctx context.Context) excludedConns {
+	conns, _ := ctx.Value(excludedConnsKey{}).(excludedConns)
+	return conns
+}
+
+// has returns true if the connection currently backing the slot is excluded
+func (e excludedConns) has(c *clientConn) bool {
+	if len(e) == 0 {
+		return false
+	}
+	conn, _ := c.current()
+	return e.includes(conn)
+}
+
+func (e excludedConns) includes(conn *sharedConn) bool {
+	for _, excluded := range e {
+		if excluded == conn {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newRetryClient(t *testing.T, policy RetryPolicy) hpb.HealthClient {
+	t.Helper()
+
+	cfg := ClientConfigBuilder().
+		WithTargets(startFailingServer(t), startServer(t)).
+		WithPoolSize(2).
+		WithMinReadyConns(2).
+		WithRetryPolicy(policy).
+		Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close(context.Background()) })
+	return hpb.NewHealthClient(c)
+}
+
+func TestRetryGoesToAnotherConn(t *testing.T) {
+	// every RPC served from the failing connection succeeds only if its retry goes to the other one
+	hc := newRetryClient(t, RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, BudgetTokens: 100})
+	for i := 0; i < 50; i++ {
+		if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+			t.Fatalf("RPC [%d] failed, error is: [%v]", i, err)
+		}
+	}
+}
+
+func TestRetryBudgetStopsRetries(t *testing.T) {
+	hc := newRetryClient(t, RetryPolicy{InitialBackoff: time.Millisecond})
+
+	// half the RPCs are served from the failing connection, each of which takes 0.9 tokens net of its successful retry
+	firstFailure := -1
+	for i := 0; i < 40 && firstFailure < 0; i++ {
+		if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err != nil {
+			firstFailure = i
+		}
+	}
+	if firstFailure < 10 || firstFailure >= 30 {
+		t.Fatalf("first RPC failed on the budget at [%d], expected it after about 10 retries", firstFailure)
+	}
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// scheduler runs a job at a fixed interval, with a random jitter added to every interval
+// so that the jobs of multiple pools do not run in lockstep
+type scheduler struct {
+	interval time.Duration
+	jitter   float64
+	clock    Clock
+	rnd      *rand.Rand
+}
+
+// newScheduler creates a scheduler running every interval, jittered by +/- jitter fraction of the interval.
+// The jitter is clamped to [0, maxSchedulerJitter], so that an interval never drops to 0 and runs the job in a hot loop
+func newScheduler(interval time.Duration, jitter float64, clk Clock) *scheduler {
+	jitter = math.Max(0, math.Min(jitter, maxSchedulerJitter))
+	return &scheduler{
+		interval: interval,
+		jitter:   jitter,
+		clock:    clk,
+		rnd:      rand.New(rand.NewSource(clk.Now().UnixNano())),
+	}
+}
+
+// run blocks, running the job at every tick till ctx is done. A job never overlaps with the previous one
+func (s *scheduler) run(ctx context.Context, job func()) {
+	for {
+		t := s.clock.NewTimer(s.next())
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C():
+		}
+		job()
+	}
+}
+
+// next returns the time till the next tick
+func (s *scheduler) next() time.Duration {
+	if s.jitter <= 0 {
+		return s.interval
+	}
+	delta := (s.rnd.Float64()*2 - 1) * s.jitter * float64(s.interval)
+	return s.interval + time.Duration(delta)
+}
@@ -1,13 +1,85 @@
 package grpc
 
 import (
+	"context"
+	"math"
+	"math/rand"
 	"sync"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
 )
 
 type Selector interface {
 	Select(max int) int
 }
 
+// ConnSelector is an extended Selector, which decides on the connection based upon the RPC being made
+// along with the state and stats of every connection in the pool.
+// It returns the index of the selected connection in SelectInfo.Conns
+type ConnSelector interface {
+	SelectConn(info SelectInfo) int
+}
+
+// SelectInfo carries the RPC being made and a read-only snapshot of the connections in the pool
+type SelectInfo struct {
+	Ctx    context.Context
+	Method string
+	Conns  []ConnInfo
+}
+
+// ConnInfo is a read-only snapshot of the state and stats of a connection in the pool
+type ConnInfo struct {
+	State     connectivity.State
+	Healthy   bool
+	InFlight  int64
+	CreatedAt time.Time
+	Lifetime  time.Duration
+	Requests  int64
+	Bytes     int64
+	Peer      string
+	Target    string
+	// Age is the time since the connection was dialled, as per the clock of the pool
+	Age time.Duration
+	// Latency is the moving average of the latency of the successful unary RPCs of the connection, 0 till it has served one
+	Latency time.Duration
+}
+
+// AdaptSelector adapts a Selector to the ConnSelector interface
+func AdaptSelector(s Selector) ConnSelector {
+	if cs, ok := s.(ConnSelector); ok {
+		return cs
+	}
+	return selectorAdapter{s: s}
+}
+
+type selectorAdapter struct {
+	s Selector
+}
+
+func (a selectorAdapter) SelectConn(info SelectInfo) int {
+	if ls, ok := a.s.(LoadAwareSelector); ok {
+		inFlight := make([]int64, len(info.Conns))
+		for i, c := range info.Conns {
+			inFlight[i] = c.InFlight
+			// an unhealthy connection has no RPCs in-flight, it should not look like the least loaded one
+			if !c.Healthy {
+				inFlight[i] = math.MaxInt64
+			}
+		}
+		return ls.SelectByLoad(inFlight)
+	}
+	return a.s.Select(len(info.Conns))
+}
+
+// LoadAwareSelector is a Selector which decides on the connection based upon
+// the no of RPCs in-flight over each connection in the pool.
+// An unhealthy connection is reported with math.MaxInt64 RPCs in-flight
+type LoadAwareSelector interface {
+	Selector
+	SelectByLoad(inFlight []int64) int
+}
+
 // RoundRobinSelector implements Selector interface to decide on how to
 // decide on the iteration logic over list of connections
 type RoundRobinSelector struct {
@@ -16,11 +88,80 @@ type RoundRobinSelector struct {
 }
 
 func (rr *RoundRobinSelector) Select(max int) int {
+	if max <= 0 {
+		return 0
+	}
+
 	rr.mu.Lock()
 	defer rr.mu.Unlock()
 
-	i := rr.i
-	rr.i = (rr.i + 1) % max
+	// pool might have shrunk since the last selection
+	i := rr.i % max
+	rr.i = (i + 1) % max
 
 	return i
 }
+
+// LeastOutstandingSelector implements LoadAwareSelector interface to pick the connection
+// with the least no of RPCs in-flight. Ties are broken in a round-robin manner
+type LeastOutstandingSelector struct {
+	rr RoundRobinSelector
+}
+
+func (s *LeastOutstandingSelector) Select(max int) int { return s.rr.Select(max) }
+
+func (s *LeastOutstandingSelector) SelectByLoad(inFlight []int64) int {
+	n := len(inFlight)
+	// start from a rotating offset so that ties are spread across all the connections
+	start := s.rr.Select(n)
+	best := start
+	for i := 1; i < n; i++ {
+		j := (start + i) % n
+		if inFlight[j] < inFlight[best] {
+			best = j
+		}
+	}
+	return best
+}
+
+// PowerOfTwoChoicesSelector implements LoadAwareSelector interface to pick 2 connections at random
+// and select the one with fewer RPCs in-flight
+type PowerOfTwoChoicesSelector struct {
+	rnd *rand.Rand
+	mu  sync.Mutex
+}
+
+func (s *PowerOfTwoChoicesSelector) Select(max int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.random().Intn(max)
+}
+
+func (s *PowerOfTwoChoicesSelector) SelectByLoad(inFlight []int64) int {
+	n := len(inFlight)
+	if n == 1 {
+		return 0
+	}
+
+	s.mu.Lock()
+	i := s.random().Intn(n)
+	j := s.random().Intn(n - 1)
+	s.mu.Unlock()
+
+	// pick j from all the connections except i
+	if j >= i {
+		j++
+	}
+	if inFlight[j] < inFlight[i] {
+		return j
+	}
+	return i
+}
+
+func (s *PowerOfTwoChoicesSelector) random() *rand.Rand {
+	if s.rnd == nil {
+		s.rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return s.rnd
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestSelectorAdapterSkipsUnhealthyConns(t *testing.T) {
+	selectors := map[string]Selector{
+		"least outstanding":    &LeastOutstandingSelector{},
+		"power of two choices": &PowerOfTwoChoicesSelector{},
+	}
+	for name, s := range selectors {
+		t.Run(name, func(t *testing.T) {
+			cs := AdaptSelector(s)
+			// the dead connection has no RPCs in-flight, and would win on load alone
+			info := SelectInfo{Conns: []ConnInfo{
+				{Healthy: true, InFlight: 5},
+				{Healthy: false, InFlight: 0},
+				{Healthy: true, InFlight: 3},
+			}}
+			for i := 0; i < 100; i++ {
+				if idx := cs.SelectConn(info); idx == 1 {
+					t.Fatalf("selected the unhealthy connection")
+				}
+			}
+		})
+	}
+}
+
+func TestSelectorAdapterAllUnhealthy(t *testing.T) {
+	cs := AdaptSelector(&LeastOutstandingSelector{})
+	info := SelectInfo{Conns: []ConnInfo{{InFlight: 2}, {InFlight: 0}}}
+	if idx := cs.SelectConn(info); idx < 0 || idx >= len(info.Conns) {
+		t.Fatalf("selected index [%d] out of range", idx)
+	}
+}
+
+type outOfRangeSelector struct{}
+
+func (outOfRangeSelector) SelectConn(info SelectInfo) int { return len(info.Conns) }
+
+func TestOutOfRangeSelectionFailsRPC(t *testing.T) {
+	cfg := ClientConfigBuilder().WithTarget(startServer(t)).WithPoolSize(2).WithConnSelector(outOfRangeSelector{}).Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+
+	hc := hpb.NewHealthClient(c)
+	if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); !errors.Is(err, ErrNoHealthyConn) {
+		t.Fatalf("expected [%v], got [%v]", ErrNoHealthyConn, err)
+	}
+	if _, err := hc.Watch(context.Background(), &hpb.HealthCheckRequest{}); !errors.Is(err, ErrNoHealthyConn) {
+		t.Fatalf("expected [%v], got [%v]", ErrNoHealthyConn, err)
+	}
+}
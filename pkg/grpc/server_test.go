@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// startServer starts a grpc server serving the health service, which is stopped once the test is over
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	hpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+// startFailingServer starts a grpc server failing every RPC with UNAVAILABLE, which is stopped once the test is over
+func startFailingServer(t *testing.T) string {
+	return startCountingFailingServer(t, new(int64))
+}
+
+// startCountingFailingServer starts a grpc server failing every RPC with UNAVAILABLE, counting the RPCs into served
+func startCountingFailingServer(t *testing.T, served *int64) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(grpc.UnknownServiceHandler(func(any, grpc.ServerStream) error {
+		atomic.AddInt64(served, 1)
+		return status.Error(codes.Unavailable, "unavailable")
+	}))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
+
+type slowHealth struct {
+	hpb.UnimplementedHealthServer
+	d time.Duration
+}
+
+func (s slowHealth) Check(ctx context.Context, _ *hpb.HealthCheckRequest) (*hpb.HealthCheckResponse, error) {
+	select {
+	case <-time.After(s.d):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &hpb.HealthCheckResponse{Status: hpb.HealthCheckResponse_SERVING}, nil
+}
+
+// startSlowServer starts a grpc server answering the health checks after d, which is stopped once the test is over
+func startSlowServer(t *testing.T, d time.Duration) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	hpb.RegisterHealthServer(s, slowHealth{d: d})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return lis.Addr().String()
+}
@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultShadowMaxConcurrent = 10
+	defaultShadowTimeout       = 5 * time.Second
+)
+
+// ShadowConfig configures a ShadowClient
+type ShadowConfig struct {
+	Primary Client
+	Shadow  Client
+	// SampleRate is the share of the unary RPCs, between 0 and 1, which are mirrored to the shadow
+	SampleRate float64
+	// MaxConcurrent is the max no of shadow RPCs in-flight, RPCs sampled beyond it are not mirrored
+	MaxConcurrent int
+	// Timeout of every shadow RPC
+	Timeout time.Duration
+	// OnResult gets called with the outcome of every shadow RPC
+	OnResult func(ShadowResult)
+}
+
+// ShadowResult compares the outcome of a mirrored RPC on the primary and the shadow
+type ShadowResult struct {
+	Method         string
+	PrimaryErr     error
+	ShadowErr      error
+	PrimaryLatency time.Duration
+	ShadowLatency  time.Duration
+	// Mismatch is true if the status codes or the replies of the primary and the shadow differ
+	Mismatch bool
+}
+
+// ShadowClient implements Client interface to serve all the RPCs from the primary client, while asynchronously
+// mirroring a sampled share of the unary RPCs to the shadow client, e.g. to validate a new backend before migrating to it.
+// The replies of the shadow are discarded, and a shadow RPC never slows down or fails the primary RPC
+type ShadowClient struct {
+	cfg     ShadowConfig
+	sem     chan struct{}
+	dropped int64
+	rnd     *rand.Rand
+	rndMu   sync.Mutex
+	wg      sync.WaitGroup
+}
+
+var (
+	_ Client       = (*ShadowClient)(nil)
+	_ PeerReporter = (*ShadowClient)(nil)
+)
+
+func NewShadowClient(cfg ShadowConfig) (*ShadowClient, error) {
+	if cfg.Primary == nil || cfg.Shadow == nil {
+		return nil, fmt.Errorf("[%w], primary and shadow clients are required", ErrInvalidShadow)
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("[%w], sample rate [%v] not between 0 and 1", ErrInvalidShadow, cfg.SampleRate)
+	}
+	cfg.MaxConcurrent = GetOrDefault[int](cfg.MaxConcurrent, defaultShadowMaxConcurrent)
+	cfg.Timeout = GetOrDefault[time.Duration](cfg.Timeout, defaultShadowTimeout)
+
+	return &ShadowClient{
+		cfg: cfg,
+		sem: make(chan struct{}, cfg.MaxConcurrent),
+		rnd: rand.New(rand.NewSource(rand.Int63())),
+	}, nil
+}
+
+func (s *ShadowClient) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	in, isProto := args.(proto.Message)
+	out, _ := reply.(proto.Message)
+	// only proto messages can be copied for the shadow, so that the caller is free to reuse them
+	if !isProto || out == nil || !s.sample() || !s.acquire() {
+		return s.cfg.Primary.Invoke(ctx, method, args, reply, opts...)
+	}
+
+	primary := make(chan primaryOutcome, 1)
+	s.wg.Add(1)
+	go s.mirror(detach(ctx), method, proto.Clone(in), out.ProtoReflect().New().Interface(), primary)
+
+	start := time.Now()
+	err := s.cfg.Primary.Invoke(ctx, method, args, reply, opts...)
+	outcome := primaryOutcome{err: err, latency: time.Since(start)}
+	if err == nil {
+		// reply is handed over to the caller, the shadow compares against a copy of it
+		outcome.reply = proto.Clone(out)
+	}
+	primary <- outcome
+	return err
+}
+
+type primaryOutcome struct {
+	err     error
+	latency time.Duration
+	reply   proto.Message
+}
+
+func (s *ShadowClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return s.cfg.Primary.NewStream(ctx, desc, method, opts...)
+}
+
+// Close waits for the shadow RPCs in-flight, and closes both the primary and the shadow clients
+func (s *ShadowClient) Close(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return errors.Join(s.cfg.Shadow.Close(ctx), s.cfg.Primary.Close(ctx))
+}
+
+// PeerDistribution returns the no of pooled connections of the primary client landed on every backend,
+// it is empty if the primary client does not implement PeerReporter
+func (s *ShadowClient) PeerDistribution() map[string]int {
+	if r, ok := s.cfg.Primary.(PeerReporter); ok {
+		return r.PeerDistribution()
+	}
+	return map[string]int{}
+}
+
+// Dropped returns the no of sampled RPCs which were not mirrored, as MaxConcurrent shadow RPCs were in-flight
+func (s *ShadowClient) Dropped() int64 { return atomic.LoadInt64(&s.dropped) }
+
+func (s *ShadowClient) sample() bool {
+	s.rndMu.Lock()
+	defer s.rndMu.Unlock()
+
+	return s.rnd.Float64() < s.cfg.SampleRate
+}
+
+func (s *ShadowClient) acquire() bool {
+	select {
+	case s.sem <- struct{}{}:
+		return true
+	default:
+		atomic.AddInt64(&s.dropped, 1)
+		return false
+	}
+}
+
+// mirror sends the RPC to the shadow, and reports its outcome against the outcome of the primary.
+// The call options of the primary RPC are not passed on, as they may write the headers or peer into the caller's variables
+func (s *ShadowClient) mirror(ctx context.Context, method string, args, reply proto.Message, primary <-chan primaryOutcome) {
+	defer s.wg.Done()
+	defer func() { <-s.sem }()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.cfg.Shadow.Invoke(ctx, method, args, reply)
+	latency := time.Since(start)
+
+	p := <-primary
+	if s.cfg.OnResult == nil {
+		return
+	}
+	s.cfg.OnResult(ShadowResult{
+		Method:         method,
+		PrimaryErr:     p.err,
+		ShadowErr:      err,
+		PrimaryLatency: p.latency,
+		ShadowLatency:  latency,
+		Mismatch:       status.Code(p.err) != status.Code(err) || (err == nil && !proto.Equal(p.reply, reply)),
+	})
+}
+
+// detach returns a context carrying the outgoing grpc metadata of ctx, which is not cancelled along with ctx
+func detach(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return context.Background()
+	}
+	return metadata.NewOutgoingContext(context.Background(), md.Copy())
+}
@@ -0,0 +1,218 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// SplitBackend is a client along with its share of the RPCs of a SplitClient
+type SplitBackend struct {
+	Name   string
+	Client Client
+	Weight int
+}
+
+// SplitConfig configures a SplitClient
+type SplitConfig struct {
+	Backends []SplitBackend
+	// StickyKey is the outgoing grpc metadata key, the RPCs carrying the same value for which are sent to the same backend.
+	// RPCs without the key are split at random. A change of weights only moves the keys at the boundaries of the backends,
+	// in the order of Backends, e.g. the keys on the last backend stay on it while its weight goes up
+	StickyKey string
+}
+
+// SplitStats reports the share of the RPCs and the no of RPCs served by a backend of a SplitClient
+type SplitStats struct {
+	Name      string
+	Weight    int
+	Successes int64
+	Errors    int64
+}
+
+// SplitClient implements Client interface to split the RPCs between multiple clients in proportion to their weights,
+// e.g. to send a small percentage of the traffic to a canary. The weights can be changed at runtime
+type SplitClient struct {
+	backends []*splitBackend
+	keyFn    HashKeyFunc
+	rnd      *rand.Rand
+	rndMu    sync.Mutex
+}
+
+var (
+	_ Client       = (*SplitClient)(nil)
+	_ PeerReporter = (*SplitClient)(nil)
+)
+
+type splitBackend struct {
+	name      string
+	client    Client
+	weight    int64
+	successes int64
+	errors    int64
+}
+
+func NewSplitClient(cfg SplitConfig) (*SplitClient, error) {
+	if len(cfg.Backends) < 2 {
+		return nil, fmt.Errorf("[%w], [%d] backends, at least 2 are required", ErrInvalidSplit, len(cfg.Backends))
+	}
+
+	s := &SplitClient{
+		backends: make([]*splitBackend, 0, len(cfg.Backends)),
+		rnd:      rand.New(rand.NewSource(rand.Int63())),
+	}
+	names := make(map[string]bool, len(cfg.Backends))
+	for _, b := range cfg.Backends {
+		if b.Client == nil || b.Weight < 0 || names[b.Name] {
+			return nil, fmt.Errorf("[%w], invalid backend [%s]", ErrInvalidSplit, b.Name)
+		}
+		names[b.Name] = true
+		s.backends = append(s.backends, &splitBackend{name: b.Name, client: b.Client, weight: int64(b.Weight)})
+	}
+	if cfg.StickyKey != "" {
+		s.keyFn = MetadataHashKey(cfg.StickyKey)
+	}
+	return s, nil
+}
+
+func (s *SplitClient) Invoke(ctx context.Context, method string, args any, reply any, opts ...grpc.CallOption) error {
+	b, err := s.pick(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = b.client.Invoke(ctx, method, args, reply, opts...)
+	b.record(err)
+	return err
+}
+
+func (s *SplitClient) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	b, err := s.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// the outcome of a stream is only known once it finishes
+	once := sync.Once{}
+	finish := func(err error) { once.Do(func() { b.record(err) }) }
+
+	callOpts := make([]grpc.CallOption, 0, len(opts)+1)
+	callOpts = append(callOpts, opts...)
+	callOpts = append(callOpts, grpc.OnFinish(finish))
+
+	stream, err := b.client.NewStream(ctx, desc, method, callOpts...)
+	if err != nil {
+		finish(err)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// Close closes all the backend clients
+func (s *SplitClient) Close(ctx context.Context) error {
+	errs := make([]error, 0)
+	for _, b := range s.backends {
+		if err := b.client.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PeerDistribution returns the no of pooled connections landed on every backend, across all the backend clients
+// which implement PeerReporter
+func (s *SplitClient) PeerDistribution() map[string]int {
+	dist := make(map[string]int)
+	for _, b := range s.backends {
+		r, ok := b.client.(PeerReporter)
+		if !ok {
+			continue
+		}
+		for peer, n := range r.PeerDistribution() {
+			dist[peer] += n
+		}
+	}
+	return dist
+}
+
+// SetWeight changes the share of the RPCs sent to the named backend
+func (s *SplitClient) SetWeight(name string, weight int) error {
+	if weight < 0 {
+		return fmt.Errorf("[%w], negative weight [%d] for backend [%s]", ErrInvalidSplit, weight, name)
+	}
+	for _, b := range s.backends {
+		if b.name == name {
+			atomic.StoreInt64(&b.weight, int64(weight))
+			return nil
+		}
+	}
+	return fmt.Errorf("[%w], unknown backend [%s]", ErrInvalidSplit, name)
+}
+
+// Stats returns the weight and the no of successful and failed RPCs of every backend
+func (s *SplitClient) Stats() []SplitStats {
+	stats := make([]SplitStats, 0, len(s.backends))
+	for _, b := range s.backends {
+		stats = append(stats, SplitStats{
+			Name:      b.name,
+			Weight:    int(atomic.LoadInt64(&b.weight)),
+			Successes: atomic.LoadInt64(&b.successes),
+			Errors:    atomic.LoadInt64(&b.errors),
+		})
+	}
+	return stats
+}
+
+// pick picks the backend of an RPC, in proportion to the weights of the backends
+func (s *SplitClient) pick(ctx context.Context) (*splitBackend, error) {
+	weights := make([]int64, len(s.backends))
+	total := int64(0)
+	for i, b := range s.backends {
+		weights[i] = atomic.LoadInt64(&b.weight)
+		total += weights[i]
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("[%w], all the backends have 0 weight", ErrInvalidSplit)
+	}
+
+	var point int64
+	if key, ok := s.key(ctx); ok {
+		// the hash is scaled into [0, total) instead of taken modulo total, so that a key keeps its backend as long as
+		// the boundaries of the backends do not move past it, e.g. a key stays on the canary while its weight ramps up
+		hi, _ := bits.Mul64(hashOf(key), uint64(total))
+		point = int64(hi)
+	} else {
+		s.rndMu.Lock()
+		point = s.rnd.Int63n(total)
+		s.rndMu.Unlock()
+	}
+
+	for i, w := range weights {
+		if point < w {
+			return s.backends[i], nil
+		}
+		point -= w
+	}
+	return s.backends[len(s.backends)-1], nil
+}
+
+func (s *SplitClient) key(ctx context.Context) (string, bool) {
+	if s.keyFn == nil {
+		return "", false
+	}
+	return s.keyFn(ctx)
+}
+
+func (b *splitBackend) record(err error) {
+	if err != nil {
+		atomic.AddInt64(&b.errors, 1)
+		return
+	}
+	atomic.AddInt64(&b.successes, 1)
+}
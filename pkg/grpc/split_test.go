@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestNewSplitClientRequiresTwoBackends(t *testing.T) {
+	for _, backends := range [][]SplitBackend{
+		nil,
+		{{Name: "stable", Client: &client{}, Weight: 1}},
+	} {
+		if _, err := NewSplitClient(SplitConfig{Backends: backends}); !errors.Is(err, ErrInvalidSplit) {
+			t.Fatalf("[%d] backends: expected [%v], got [%v]", len(backends), ErrInvalidSplit, err)
+		}
+	}
+
+	if _, err := NewSplitClient(SplitConfig{Backends: []SplitBackend{
+		{Name: "stable", Client: &client{}, Weight: 9},
+		{Name: "canary", Client: &client{}, Weight: 1},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestSplit(t *testing.T, stable, canary int) *SplitClient {
+	t.Helper()
+
+	s, err := NewSplitClient(SplitConfig{
+		Backends: []SplitBackend{
+			{Name: "stable", Client: &client{}, Weight: stable},
+			{Name: "canary", Client: &client{}, Weight: canary},
+		},
+		StickyKey: "tenant",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSplitStickyKeysStayOnCanaryWhileRampingUp(t *testing.T) {
+	s := newTestSplit(t, 95, 5)
+	pickAll := func() map[string]string {
+		picked := make(map[string]string, 1000)
+		for i := 0; i < 1000; i++ {
+			tenant := fmt.Sprintf("tenant-%d", i)
+			b, err := s.pick(metadata.AppendToOutgoingContext(context.Background(), "tenant", tenant))
+			if err != nil {
+				t.Fatal(err)
+			}
+			picked[tenant] = b.name
+		}
+		return picked
+	}
+
+	prev := pickAll()
+	ramp := []struct{ stable, canary int }{{90, 10}, {90, 20}, {75, 25}, {50, 50}, {50, 100}}
+	for _, w := range ramp {
+		if err := s.SetWeight("stable", w.stable); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetWeight("canary", w.canary); err != nil {
+			t.Fatal(err)
+		}
+		curr := pickAll()
+		for tenant, backend := range prev {
+			if backend == "canary" && curr[tenant] != "canary" {
+				t.Fatalf("[%s] moved off the canary when its weights became [%d/%d]", tenant, w.stable, w.canary)
+			}
+		}
+		prev = curr
+	}
+}
+
+func TestSplitWeightedPicksFollowSetWeight(t *testing.T) {
+	s := newTestSplit(t, 90, 10)
+	share := func() float64 {
+		canary := 0
+		for i := 0; i < 10000; i++ {
+			b, err := s.pick(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b.name == "canary" {
+				canary++
+			}
+		}
+		return float64(canary) / 10000
+	}
+
+	if got := share(); math.Abs(got-0.1) > 0.02 {
+		t.Fatalf("canary got [%v] of the RPCs at 10%% weight", got)
+	}
+	if err := s.SetWeight("canary", 30); err != nil {
+		t.Fatal(err)
+	}
+	if got := share(); math.Abs(got-0.25) > 0.02 {
+		t.Fatalf("canary got [%v] of the RPCs at 25%% weight", got)
+	}
+}
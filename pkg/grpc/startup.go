@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// awaitReady blocks till at least minReady connections of the pool are in READY state.
+// If ctx is done before that, it returns the dial errors along with the context error and the state of the connections
+func (pool *clientConnPool) awaitReady(ctx context.Context, minReady int, dialErrs []error) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	// the state of every connection is watched instead of polled, so that no time is spent outside the pool clock
+	changed := make(chan struct{}, 1)
+	watched := make(map[*sharedConn]bool)
+	watch := func() {
+		for _, c := range pool.snapshot() {
+			conn, ok := c.current()
+			if !ok || watched[conn] {
+				continue
+			}
+			watched[conn] = true
+			conn.Connect()
+			state := conn.GetState()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for conn.WaitForStateChange(watchCtx, state) {
+					state = conn.GetState()
+					select {
+					case changed <- struct{}{}:
+					default:
+					}
+				}
+			}()
+		}
+	}
+
+	for {
+		// connections added by the healer or a resize, and the ones replaced by a refresh, are watched as well
+		connsChanged := pool.connsChanged()
+		watch()
+
+		ready := pool.readyConns()
+		if ready >= minReady {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("[%w], [%d] out of min [%d] connections ready, error is: [%w]", ErrNotReady, ready, minReady, pool.notReadyErr(ctx.Err(), dialErrs))
+		case <-changed:
+		case <-connsChanged:
+		}
+	}
+}
+
+// notReadyErr joins the context error, the dial errors and the state of every connection which is not ready
+func (pool *clientConnPool) notReadyErr(ctxErr error, dialErrs []error) error {
+	errs := append([]error{ctxErr}, dialErrs...)
+	// the last dial error is one of the dial errors of the startup, if any
+	if err := pool.DialErr(); err != nil && len(dialErrs) == 0 {
+		errs = append(errs, err)
+	}
+	for _, c := range pool.snapshot() {
+		if info := c.info(); info.State != connectivity.Ready {
+			errs = append(errs, fmt.Errorf("connection to [%s] in [%s] state", info.Target, info.State))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readyConns returns the no of connections in the pool which are in READY state
+func (pool *clientConnPool) readyConns() int {
+	ready := 0
+	for _, c := range pool.snapshot() {
+		if c.info().State == connectivity.Ready {
+			ready++
+		}
+	}
+	return ready
+}
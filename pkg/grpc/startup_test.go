@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// unreachableTarget returns an address no server is listening on
+func unreachableTarget(t *testing.T) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	return addr
+}
+
+func TestNotReadyErrorCarriesConnState(t *testing.T) {
+	target := unreachableTarget(t)
+	cfg := ClientConfigBuilder().WithTarget(target).WithMinReadyConns(1).WithReadyTimeout(300 * time.Millisecond).Build()
+	_, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !errors.Is(err, ErrNotReady) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected [%v], got [%v]", ErrNotReady, err)
+	}
+	if !strings.Contains(err.Error(), target) {
+		t.Fatalf("error does not name the unreachable target [%s], error is: [%v]", target, err)
+	}
+}
+
+func TestAwaitReadyWatchesNewConns(t *testing.T) {
+	cfg := ClientConfigBuilder().WithTarget(unreachableTarget(t)).Build()
+	c, err := NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close(context.Background())
+	pool := c.(*client).pool
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	ready := make(chan error, 1)
+	go func() { ready <- pool.awaitReady(ctx, 1, nil) }()
+
+	// a connection added to the pool after the wait started gets ready well before the unreachable one is retried
+	time.Sleep(100 * time.Millisecond)
+	pool.targets.Store(newTargetPicker([]Target{{Addr: startServer(t)}}))
+	if err := pool.Resize(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-ready; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc/stats"
+)
+
+// connStats is a grpc stats handler installed by the pool on every connection it dials,
+// to record the no of RPCs completed and the bytes sent and received over the connection.
+// Once the usage of the connection expires it as per the lifetime policy of the pool, onExpired gets called once
+type connStats struct {
+	requests  int64
+	bytes     int64
+	expired   func(requests, bytes int64) bool
+	_expired  uint32
+	onExpired func()
+	peer      atomic.Value
+}
+
+func (s *connStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context { return ctx }
+
+func (s *connStats) HandleRPC(_ context.Context, rs stats.RPCStats) {
+	switch st := rs.(type) {
+	case *stats.End:
+		atomic.AddInt64(&s.requests, 1)
+	case *stats.InPayload:
+		atomic.AddInt64(&s.bytes, int64(st.WireLength))
+	case *stats.OutPayload:
+		atomic.AddInt64(&s.bytes, int64(st.WireLength))
+	default:
+		return
+	}
+	s.checkUsage()
+}
+
+// checkUsage refreshes the connection right away once its usage expires it, without waiting for the next refresh interval
+func (s *connStats) checkUsage() {
+	if s.expired == nil || atomic.LoadUint32(&s._expired) == 1 || !s.expired(s.Requests(), s.Bytes()) {
+		return
+	}
+	if atomic.CompareAndSwapUint32(&s._expired, 0, 1) && s.onExpired != nil {
+		s.onExpired()
+	}
+}
+
+// TagConn records the remote address of the backend, every time the connection (re)connects
+func (s *connStats) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	if info.RemoteAddr != nil {
+		s.peer.Store(info.RemoteAddr.String())
+	}
+	return ctx
+}
+
+func (s *connStats) HandleConn(context.Context, stats.ConnStats) {}
+
+func (s *connStats) Requests() int64 { return atomic.LoadInt64(&s.requests) }
+
+func (s *connStats) Bytes() int64 { return atomic.LoadInt64(&s.bytes) }
+
+// Peer returns the remote address of the backend the connection is on, empty if it has not connected yet
+func (s *connStats) Peer() string {
+	peer, _ := s.peer.Load().(string)
+	return peer
+}
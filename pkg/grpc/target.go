@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"sync"
+)
+
+// Target is a backend address of a multi target pool, along with its share of the pool connections.
+// A weight of 0 or less is treated as 1
+type Target struct {
+	Addr   string
+	Weight int
+}
+
+// targetPicker spreads the pool connections over the targets using smooth weighted round robin,
+// so that with equal weights the targets are picked one after the other
+type targetPicker struct {
+	targets []Target
+	current []int
+	mu      sync.Mutex
+}
+
+func newTargetPicker(targets []Target) *targetPicker {
+	t := make([]Target, 0, len(targets))
+	for _, target := range targets {
+		if target.Weight <= 0 {
+			target.Weight = 1
+		}
+		t = append(t, target)
+	}
+	return &targetPicker{targets: t, current: make([]int, len(t))}
+}
+
+// next returns the target the next connection should be dialled to, skipping the excluded one
+// unless it is the only target
+func (p *targetPicker) next(exclude string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	best, total := -1, 0
+	for i, target := range p.targets {
+		if target.Addr == exclude && len(p.targets) > 1 {
+			continue
+		}
+		p.current[i] += target.Weight
+		total += target.Weight
+		if best < 0 || p.current[i] > p.current[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return exclude
+	}
+	p.current[best] -= total
+	return p.targets[best].Addr
+}
+
+// has returns true if addr is one of the targets
+func (p *targetPicker) has(addr string) bool {
+	for _, target := range p.targets {
+		if target.Addr == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// picker returns the targets the connections of the pool are dialled to
+func (pool *clientConnPool) picker() *targetPicker { return pool.targets.Load().(*targetPicker) }
+
+// recordSlotFailure records a failure of the slot to dial a connection to its target
+func (pool *clientConnPool) recordSlotFailure(c *clientConn) {
+	c.cMu.Lock()
+	defer c.cMu.Unlock()
+
+	pool.recordFailure(c)
+}
+
+// recordFailure records a failure of the connection backing the slot to reach its target.
+// Once the target has failed for the failover threshold no of times in a row, the slot fails over to another target.
+// It should be called with cMu held
+func (pool *clientConnPool) recordFailure(c *clientConn) {
+	c.failures++
+	if c.failures < targetFailoverThreshold {
+		return
+	}
+	c.failures = 0
+	c.target = pool.picker().next(c.target)
+}
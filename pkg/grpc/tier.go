@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+const (
+	defaultTierInterval      = 5 * time.Second
+	defaultTierFailbackAfter = time.Minute
+	defaultTierMinHealthy    = 1
+)
+
+const (
+	TierFailoverReason = "healthy connections to the active tier below min healthy"
+	TierFailbackReason = "higher tier stable for the failback period"
+)
+
+// TierConfig configures the pool to fail over between ordered tiers of targets, the first tier being the primary.
+// The pool only dials the targets of a lower tier when the higher tier is not healthy enough
+type TierConfig struct {
+	Tiers [][]Target
+	// MinHealthy is the min no of READY connections to the active tier, which are not ejected,
+	// below which the pool fails over to the next tier
+	MinHealthy int
+	// FailbackAfter is the period a higher tier should stay reachable before the pool moves back to it
+	FailbackAfter time.Duration
+	// Interval at which the health of the tiers is evaluated
+	Interval time.Duration
+	// OnEvent gets called with every tier transition
+	OnEvent func(TierEvent)
+}
+
+// TierEvent reports a transition of the pool from one tier of targets to another
+type TierEvent struct {
+	From       int
+	To         int
+	Healthy    int
+	Reason     string
+	OccurredAt time.Time
+}
+
+// WithTargetTiers turns on the priority based failover of the pool between the tiers of targets
+func WithTargetTiers(cfg TierConfig) Option {
+	return optionFunc(func(o *options) {
+		o.tiers = &cfg
+	})
+}
+
+func (cfg TierConfig) withDefaults() TierConfig {
+	tiers := make([][]Target, 0, len(cfg.Tiers))
+	for _, tier := range cfg.Tiers {
+		if len(tier) > 0 {
+			tiers = append(tiers, tier)
+		}
+	}
+	cfg.Tiers = tiers
+	cfg.MinHealthy = GetOrDefault[int](cfg.MinHealthy, defaultTierMinHealthy)
+	cfg.FailbackAfter = GetOrDefault[time.Duration](cfg.FailbackAfter, defaultTierFailbackAfter)
+	cfg.Interval = GetOrDefault[time.Duration](cfg.Interval, defaultTierInterval)
+	return cfg
+}
+
+// watchTiers evaluates the health of the active tier every interval, till the pool is closed.
+// It fails over to the next tier when the active one is not healthy enough, and fails back once
+// a probe connection to the higher tier has been READY for the failback period
+func (pool *clientConnPool) watchTiers(cfg TierConfig) {
+	var (
+		active      int
+		probe       *sharedConn
+		stableSince time.Time
+	)
+	closeProbe := func() {
+		if probe != nil {
+			_ = probe.Close()
+			probe = nil
+		}
+	}
+	defer closeProbe()
+
+	for pool.wait(cfg.Interval) {
+		healthy := pool.healthyOnActiveTier()
+		if healthy < cfg.MinHealthy && active < len(cfg.Tiers)-1 {
+			closeProbe()
+			pool.switchTier(cfg, active, active+1, healthy, TierFailoverReason)
+			active++
+			continue
+		}
+		if active == 0 {
+			continue
+		}
+
+		if probe == nil {
+			probe, _ = pool.dial(newTargetPicker(cfg.Tiers[active-1]).next(""))
+			stableSince = time.Time{}
+			if probe == nil {
+				continue
+			}
+		}
+		if probe.GetState() != connectivity.Ready {
+			stableSince = time.Time{}
+			probe.Connect()
+			continue
+		}
+		now := pool.opts.clock.Now()
+		if stableSince.IsZero() {
+			stableSince = now
+		}
+		if now.Sub(stableSince) >= cfg.FailbackAfter {
+			closeProbe()
+			pool.switchTier(cfg, active, active-1, healthy, TierFailbackReason)
+			active--
+		}
+	}
+}
+
+// switchTier moves the pool to the targets of another tier, the connections to the previous tier are refreshed right away
+func (pool *clientConnPool) switchTier(cfg TierConfig, from, to, healthy int, reason string) {
+	pool.targets.Store(newTargetPicker(cfg.Tiers[to]))
+	if cfg.OnEvent != nil {
+		cfg.OnEvent(TierEvent{From: from, To: to, Healthy: healthy, Reason: reason, OccurredAt: pool.opts.clock.Now()})
+	}
+	pool.refreshInBackground()
+}
+
+// healthyOnActiveTier returns the no of connections to the targets of the active tier which are READY and not ejected.
+// The lifetime and budget of the connections are left out, as an expired connection gets refreshed to the same tier
+// and says nothing of the reachability of the tier
+func (pool *clientConnPool) healthyOnActiveTier() int {
+	targets := pool.picker()
+	now := pool.opts.clock.Now()
+	healthy := 0
+	for _, c := range pool.snapshot() {
+		info := c.info()
+		if !targets.has(info.Target) || info.State != connectivity.Ready {
+			continue
+		}
+		if conn, ok := c.current(); ok && pool.outlier != nil && conn.outlier.ejected(now) {
+			continue
+		}
+		healthy++
+	}
+	return healthy
+}
@@ -0,0 +1,123 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/arpit006/go-grpc-conn-pool/pkg/grpc"
+	"github.com/arpit006/go-grpc-conn-pool/pkg/grpc/grpctest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	hpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// serveOn starts a grpc server serving the health service on addr
+func serveOn(t *testing.T, addr string) *grpc.Server {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	hpb.RegisterHealthServer(s, health.NewServer())
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	return s
+}
+
+func newTierClient(t *testing.T, clk *grpctest.FakeClock, primary, dr string, events chan v2.TierEvent) v2.Client {
+	t.Helper()
+
+	// the background refresh never fires, so that the expired connections stay in the pool
+	cfg := v2.ClientConfigBuilder().
+		WithPoolSize(2).
+		WithMinReadyConns(2).
+		WithClock(clk).
+		WithLifetimePolicy(v2.FixedLifetime(time.Minute)).
+		WithRefreshInterval(time.Hour).
+		WithTargetTiers(v2.TierConfig{
+			Tiers:         [][]v2.Target{{{Addr: primary}}, {{Addr: dr}}},
+			FailbackAfter: 20 * time.Second,
+			OnEvent:       func(e v2.TierEvent) { events <- e },
+		}).
+		Build()
+	c, err := v2.NewClient(cfg, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close(context.Background()) })
+	return c
+}
+
+func TestTiersDoNotFailOverOnLifetimeExpiry(t *testing.T) {
+	clk := grpctest.NewFakeClock(time.Unix(1000, 0))
+	events := make(chan v2.TierEvent, 10)
+	newTierClient(t, clk, startServer(t), startServer(t), events)
+
+	// the connections expire at +60s, and the tiers are evaluated every 5s till +2m
+	for i := 0; i < 24; i++ {
+		// the refresh scheduler and the tier watcher are waiting on the clock
+		clk.BlockUntil(2)
+		clk.Advance(5 * time.Second)
+	}
+	clk.BlockUntil(2)
+	select {
+	case e := <-events:
+		t.Fatalf("tier transition on lifetime expiry: [%+v]", e)
+	default:
+	}
+}
+
+// advanceUntilEvent advances the clock by the tier interval till a tier transition is reported
+func advanceUntilEvent(t *testing.T, clk *grpctest.FakeClock, events chan v2.TierEvent) v2.TierEvent {
+	t.Helper()
+
+	for i := 0; i < 200; i++ {
+		select {
+		case e := <-events:
+			return e
+		default:
+		}
+		clk.Advance(5 * time.Second)
+		// let the connections change state in between
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("no tier transition")
+	return v2.TierEvent{}
+}
+
+func TestTiersFailOverAndFailBack(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary := lis.Addr().String()
+	lis.Close()
+
+	s := serveOn(t, primary)
+	clk := grpctest.NewFakeClock(time.Unix(1000, 0))
+	events := make(chan v2.TierEvent, 10)
+	c := newTierClient(t, clk, primary, startServer(t), events)
+
+	s.Stop()
+	if e := advanceUntilEvent(t, clk, events); e.From != 0 || e.To != 1 || e.Reason != v2.TierFailoverReason {
+		t.Fatalf("expected a failover to the DR tier, got [%+v]", e)
+	}
+	hc := hpb.NewHealthClient(c)
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(20 * time.Millisecond) {
+		if _, err := hc.Check(context.Background(), &hpb.HealthCheckRequest{}); err == nil {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("RPC not served from the DR tier, error is: [%v]", err)
+		}
+	}
+
+	serveOn(t, primary)
+	if e := advanceUntilEvent(t, clk, events); e.From != 1 || e.To != 0 || e.Reason != v2.TierFailbackReason {
+		t.Fatalf("expected a failback to the primary tier, got [%+v]", e)
+	}
+}
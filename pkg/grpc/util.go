@@ -31,3 +31,13 @@ func GetOrDefault[V string | int | time.Duration](v V, defaultVal V) V {
 	}
 	return v
 }
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,50 @@
+package grpc
+
+// watch triggers a refresh of the pool every time the connection backing the slot leaves READY state,
+// till the slot is removed or the pool is closed
+func (pool *clientConnPool) watch(c *clientConn) {
+	for {
+		conn, ok := c.current()
+		if !ok || pool.ctx.Err() != nil {
+			return
+		}
+
+		state := conn.GetState()
+		if isRefreshState(state) {
+			pool.triggerRefresh()
+		}
+
+		// returns false once the connection is retired, the slot is then watched over its new connection
+		if !conn.WaitForStateChange(conn.ctx, state) {
+			if next, _ := c.current(); next == conn {
+				return
+			}
+		}
+	}
+}
+
+// triggerRefresh wakes up the event driven refresh, triggers coming in while a refresh is in progress are coalesced
+func (pool *clientConnPool) triggerRefresh() {
+	select {
+	case pool.refreshCh <- struct{}{}:
+	default:
+	}
+}
+
+// refreshOnEvents refreshes the unhealthy connections on every trigger, at most once per refresh rate limit.
+// Triggers come in from the connection watchers and from the connections expiring by the RPCs or bytes served over them
+func (pool *clientConnPool) refreshOnEvents() {
+	for {
+		select {
+		case <-pool.ctx.Done():
+			return
+		case <-pool.refreshCh:
+		}
+
+		pool.refreshInBackground()
+
+		if !pool.wait(pool.opts.refreshRateLimit) {
+			return
+		}
+	}
+}